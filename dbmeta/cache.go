@@ -0,0 +1,68 @@
+package dbmeta
+
+import (
+	"database/sql"
+	"sort"
+	"strings"
+)
+
+// UniqueKeyInfo a unique index of a table, the dao reads records by it with Get<StructName>By<Name>
+type UniqueKeyInfo struct {
+	// Name go name of the key, the names of its fields joined with And
+	Name   string
+	Fields []*FieldInfo
+}
+
+// loadUniqueKeys sets the UniqueKeys of modelInfo from the indexes of the table, tables of a database without a schema loader
+// have none
+func loadUniqueKeys(db *sql.DB, sqlType string, dbMeta DbTableMeta, modelInfo *ModelInfo) error {
+	loader, ok := schemaFuncs[sqlType]
+	if !ok {
+		return nil
+	}
+
+	t := newTableSchema(dbMeta)
+	if err := loader(db, dbMeta, t); err != nil {
+		return err
+	}
+
+	modelInfo.UniqueKeys = uniqueKeys(modelInfo, t.Indexes)
+	return nil
+}
+
+// uniqueKeys unique keys of the table from its indexes, ordered by index name. Indexes of the primary key, indexes with a
+// nullable column, as NULL values are not unique, and indexes with a column that is not a string or an integer are skipped.
+func uniqueKeys(modelInfo *ModelInfo, indexes []*IndexSchema) []*UniqueKeyInfo {
+	sorted := append([]*IndexSchema{}, indexes...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	var keys []*UniqueKeyInfo
+	seen := make(map[string]bool)
+	for _, idx := range sorted {
+		if !idx.Unique || len(idx.Columns) == 0 {
+			continue
+		}
+
+		var fields []*FieldInfo
+		var names []string
+		primaryKey := true
+		for _, column := range idx.Columns {
+			f := seedField(modelInfo, column)
+			if f == nil || f.ColumnMeta.Nullable() || (f.GoFieldType != "string" && csvIntBits[f.GoFieldType] == 0) {
+				fields = nil
+				break
+			}
+			primaryKey = primaryKey && f.PrimaryKeyArgName != ""
+			fields = append(fields, f)
+			names = append(names, f.GoFieldName)
+		}
+
+		name := strings.Join(names, "And")
+		if fields == nil || primaryKey || seen[name] {
+			continue
+		}
+		seen[name] = true
+		keys = append(keys, &UniqueKeyInfo{Name: name, Fields: fields})
+	}
+	return keys
+}
@@ -0,0 +1,39 @@
+package dbmeta
+
+import (
+	"testing"
+)
+
+func Test_uniqueKeys(t *testing.T) {
+	userID := &FieldInfo{GoFieldName: "UserID", GoFieldType: "int64", PrimaryKeyArgName: "argUserID",
+		ColumnMeta: &testColumn{name: "user_id", primaryKey: true}}
+	email := &FieldInfo{GoFieldName: "Email", GoFieldType: "string", ColumnMeta: &testColumn{name: "email"}}
+	orgID := &FieldInfo{GoFieldName: "OrgID", GoFieldType: "int32", ColumnMeta: &testColumn{name: "org_id"}}
+	login := &FieldInfo{GoFieldName: "Login", GoFieldType: "string", ColumnMeta: &testColumn{name: "login"}}
+	phone := &FieldInfo{GoFieldName: "Phone", GoFieldType: "sql.NullString", ColumnMeta: &testColumn{name: "phone", nullable: true}}
+	avatar := &FieldInfo{GoFieldName: "Avatar", GoFieldType: "[]byte", ColumnMeta: &testColumn{name: "avatar"}}
+	users := &ModelInfo{StructName: "User", TableName: "users", CodeFields: []*FieldInfo{userID, email, orgID, login, phone, avatar}}
+
+	keys := uniqueKeys(users, []*IndexSchema{
+		{Name: "ux_users_org_login", Columns: []string{"org_id", "login"}, Unique: true},
+		{Name: "pk_users", Columns: []string{"user_id"}, Unique: true},
+		{Name: "ix_users_login", Columns: []string{"login"}},
+		{Name: "ux_users_phone", Columns: []string{"phone"}, Unique: true},
+		{Name: "ux_users_avatar", Columns: []string{"avatar"}, Unique: true},
+		{Name: "ux_users_email", Columns: []string{"email"}, Unique: true},
+		{Name: "ux_users_email_2", Columns: []string{"email"}, Unique: true, Constraint: true},
+	})
+
+	expected := []string{"Email", "OrgIDAndLogin"}
+	if len(keys) != len(expected) {
+		t.Fatalf("expect: %v, but got %d keys", expected, len(keys))
+	}
+	for i, key := range keys {
+		if key.Name != expected[i] {
+			t.Errorf("key %d: expect: %s, but got %s", i, expected[i], key.Name)
+		}
+	}
+	if len(keys[1].Fields) != 2 || keys[1].Fields[0] != orgID || keys[1].Fields[1] != login {
+		t.Errorf("OrgIDAndLogin: expect the org_id and login fields in index order, but got %v", keys[1].Fields)
+	}
+}
@@ -28,11 +28,13 @@ func (c *Config) GetTemplate(name, t string) (*template.Template, error) {
 		"toLowerCamelCase":  camelToLowerCamel,
 		"toSnakeCase":       snaker.CamelToSnake,
 		"markdownCodeBlock": markdownCodeBlock,
+		"markdownCell":      markdownCell,
 		"wrapBash":          wrapBash,
 		"GenerateTableFile": c.GenerateTableFile,
 		"GenerateFile":      c.GenerateFile,
 		"ToJSON":            ToJSON,
 		"StringsJoin":       strings.Join,
+		"add":               func(a, b int) int { return a + b },
 	}
 
 	tmpl, err := template.New(name).Option("missingkey=error").Funcs(funcMap).Parse(t)
@@ -42,7 +44,15 @@ func (c *Config) GetTemplate(name, t string) (*template.Template, error) {
 
 	if name == "api.go.tmpl" || name == "dao_gorm.go.tmpl" || name == "dao_sqlx.go.tmpl" || name == "code_dao_sqlx.md.tmpl" || name == "code_dao_gorm.md.tmpl" || name == "code_http.md.tmpl" {
 
-		operations := []string{"add", "delete", "get", "getall", "update"}
+		operations := []string{"add", "delete", "get", "getall", "foreach", "update", "patch", "softdelete"}
+		switch name {
+		case "api.go.tmpl":
+			operations = append(operations, "importexport")
+		case "dao_gorm.go.tmpl", "dao_sqlx.go.tmpl":
+			operations = append(operations, "cache", "import")
+		case "code_dao_sqlx.md.tmpl", "code_dao_gorm.md.tmpl":
+			operations = append(operations, "cache")
+		}
 		for _, op := range operations {
 			var filename string
 			if name == "api.go.tmpl" {
@@ -167,9 +177,40 @@ func (c *Config) CreateContextForTableFile(tableInfo *ModelInfo) map[string]inte
 		modelInfo["delSql"] = delSql
 	}
 
-	updateSql, err := GenerateUpdateSql(tableInfo.DBMeta)
+	var skipUpdateColumns []string
+	if tableInfo.CreatedAtField != nil {
+		skipUpdateColumns = append(skipUpdateColumns, tableInfo.CreatedAtField.ColumnMeta.Name())
+	}
+
+	// the soft delete column is only changed by delete and restore, soft deleted records are not updated
+	notDeleted := ""
+	if tableInfo.SoftDeleteField != nil {
+		skipUpdateColumns = append(skipUpdateColumns, tableInfo.SoftDeleteField.ColumnMeta.Name())
+		notDeleted = fmt.Sprintf(" AND %s IS NULL", tableInfo.SoftDeleteField.ColumnMeta.Name())
+	}
+
+	updateSql, err := GenerateUpdateSql(tableInfo.DBMeta, skipUpdateColumns...)
 	if err == nil {
-		modelInfo["updateSql"] = updateSql
+		modelInfo["updateSql"] = updateSql + notDeleted
+	}
+
+	if tableInfo.VersionField != nil {
+		updateVersionedSql, err := GenerateUpdateVersionedSql(tableInfo.DBMeta, tableInfo.VersionField.ColumnMeta.Name(), skipUpdateColumns...)
+		if err == nil {
+			modelInfo["updateVersionedSql"] = updateVersionedSql + notDeleted
+		}
+	}
+
+	if tableInfo.SoftDeleteField != nil {
+		softDeleteSql, err := GenerateSoftDeleteSql(tableInfo.DBMeta, tableInfo.SoftDeleteField.ColumnMeta.Name())
+		if err == nil {
+			modelInfo["softDeleteSql"] = softDeleteSql
+		}
+
+		restoreSql, err := GenerateRestoreSql(tableInfo.DBMeta, tableInfo.SoftDeleteField.ColumnMeta.Name())
+		if err == nil {
+			modelInfo["restoreSql"] = restoreSql
+		}
 	}
 
 	insertSql, err := GenerateInsertSql(tableInfo.DBMeta)
@@ -177,6 +218,21 @@ func (c *Config) CreateContextForTableFile(tableInfo *ModelInfo) map[string]inte
 		modelInfo["insertSql"] = insertSql
 	}
 
+	insertReturningSql, err := GenerateInsertReturningSql(tableInfo.DBMeta)
+	if err == nil {
+		modelInfo["insertReturningSql"] = insertReturningSql
+	}
+
+	insertOutputSql, err := GenerateInsertOutputSql(tableInfo.DBMeta)
+	if err == nil {
+		modelInfo["insertOutputSql"] = insertOutputSql
+	}
+
+	insertWithKeysSql, err := GenerateInsertWithKeysSql(tableInfo.DBMeta)
+	if err == nil {
+		modelInfo["insertWithKeysSql"] = insertWithKeysSql
+	}
+
 	selectOneSql, err := GenerateSelectOneSql(tableInfo.DBMeta)
 	if err == nil {
 		modelInfo["selectOneSql"] = selectOneSql
@@ -190,7 +246,8 @@ func (c *Config) CreateContextForTableFile(tableInfo *ModelInfo) map[string]inte
 }
 
 func (c *Config) WriteTemplate(name, templateStr string, data map[string]interface{}, outputFile string, formatOutput bool) {
-	if !c.Overwrite && Exists(outputFile) {
+	if !c.Check && !c.Overwrite && Exists(outputFile) {
+		c.OutputFiles = append(c.OutputFiles, outputFile)
 		fmt.Printf("not overwriting %s\n", outputFile)
 		return
 	}
@@ -211,6 +268,17 @@ func (c *Config) WriteTemplate(name, templateStr string, data map[string]interfa
 	data["apiFQPN"] = c.ApiFQPN
 	data["apiPackageName"] = c.ApiPackageName
 
+	data["grpcFQPN"] = c.GrpcFQPN
+	data["grpcPackageName"] = c.GrpcPackageName
+	data["pbFQPN"] = c.GrpcFQPN + "/pb"
+	data["grpcPort"] = c.GrpcPort
+
+	data["graphqlFQPN"] = c.GraphqlFQPN
+	data["graphqlPackageName"] = c.GraphqlPackageName
+
+	data["clientFQPN"] = c.ClientFQPN
+	data["clientPackageName"] = c.ClientPackageName
+
 	data["sqlType"] = c.SqlType
 	data["sqlConnStr"] = c.SqlConnStr
 	data["serverPort"] = c.ServerPort
@@ -219,6 +287,9 @@ func (c *Config) WriteTemplate(name, templateStr string, data map[string]interfa
 	data["outDir"] = c.OutDir
 	data["CommandLine"] = c.CmdLine
 	data["Config"] = c
+	if router, err := LookupRouter(c.Router); err == nil {
+		data["router"] = router
+	}
 
 	rt, err := c.GetTemplate(name, templateStr)
 	if err != nil {
@@ -238,19 +309,50 @@ func (c *Config) WriteTemplate(name, templateStr string, data map[string]interfa
 			fmt.Printf("Error in formatting %s source: %s\n", name, err.Error())
 			formattedSource = buf.Bytes()
 		}
-		err = ioutil.WriteFile(outputFile, formattedSource, 0777)
+		err = c.WriteOutput(outputFile, formattedSource, 0777)
 	} else {
-		err = ioutil.WriteFile(outputFile, buf.Bytes(), 0777)
+		err = c.WriteOutput(outputFile, buf.Bytes(), 0777)
 	}
 
 	if err != nil {
 		fmt.Printf("error writing %s - error: %v\n", outputFile, err)
 		return
 	}
+}
+
+// StaleFile a generated file that does not match the file rendered from the current schema
+type StaleFile struct {
+	Name   string
+	Reason string
+}
+
+// WriteOutput writes a generated file and records it in OutputFiles, in Check mode the content is compared with the file instead
+// and a file that is missing or differs is recorded in StaleFiles
+func (c *Config) WriteOutput(outputFile string, content []byte, perm os.FileMode) error {
+	c.OutputFiles = append(c.OutputFiles, outputFile)
+
+	if c.Check {
+		existing, err := ioutil.ReadFile(outputFile)
+		switch {
+		case os.IsNotExist(err):
+			c.StaleFiles = append(c.StaleFiles, &StaleFile{Name: outputFile, Reason: "missing"})
+		case err != nil:
+			return err
+		case !bytes.Equal(existing, content):
+			c.StaleFiles = append(c.StaleFiles, &StaleFile{Name: outputFile, Reason: "changed"})
+		}
+		return nil
+	}
+
+	err := ioutil.WriteFile(outputFile, content, perm)
+	if err != nil {
+		return err
+	}
 
 	if c.Verbose {
 		fmt.Printf("writing %s\n", outputFile)
 	}
+	return nil
 }
 
 // Exists reports whether the named file or directory exists.
@@ -311,19 +413,42 @@ type Config struct {
 	AddGormAnnotation     bool
 	AddProtobufAnnotation bool
 	AddDBAnnotation       bool
+	AddValidateAnnotation bool
 	UseGureguTypes        bool
+	VersionColumnNames    []string
+	SoftDeleteColumnNames []string
+	CreatedAtColumnNames  []string
+	UpdatedAtColumnNames  []string
+	Router                string
 	JsonNameFormat        string
 	ProtobufNameFormat    string
+	ProtobufLock          *ProtobufLock
 	DaoPackageName        string
 	DaoFQPN               string
 	ApiPackageName        string
 	ApiFQPN               string
+	GenerateGrpc          bool
+	GrpcPackageName       string
+	GrpcFQPN              string
+	GrpcPort              int
+	GenerateGraphql       bool
+	GraphqlPackageName    string
+	GraphqlFQPN           string
+	GenerateTests         bool
+	GenerateClient        bool
+	GenerateImportExport  bool
+	GenerateCache         bool
+	ClientPackageName     string
+	ClientFQPN            string
 	Swagger               *SwaggerInfoDetails
 	ServerPort            int
 	ServerHost            string
 	Verbose               bool
 	OutDir                string
 	Overwrite             bool
+	Check                 bool
+	OutputFiles           []string
+	StaleFiles            []*StaleFile
 	CmdLine               string
 	ContextMap            map[string]interface{}
 	TemplateLoader        TemplateLoader
@@ -341,7 +466,16 @@ func NewConfig(templateLoader TemplateLoader) *Config {
 			ContactURL:   "",
 			ContactEmail: "",
 		},
-		TemplateLoader: templateLoader,
+		VersionColumnNames:    []string{"version", "lock_version"},
+		Router:                "gin",
+		GrpcPackageName:       "grpcapi",
+		GrpcPort:              9090,
+		GraphqlPackageName:    "graphqlapi",
+		ClientPackageName:     "client",
+		SoftDeleteColumnNames: []string{"deleted_at"},
+		CreatedAtColumnNames:  []string{"created_at", "create_time", "created_on"},
+		UpdatedAtColumnNames:  []string{"updated_at", "update_time", "updated_on"},
+		TemplateLoader:        templateLoader,
 	}
 	conf.CmdLine = strings.Join(os.Args, " ")
 	conf.ContextMap = make(map[string]interface{})
@@ -0,0 +1,102 @@
+package dbmeta
+
+import (
+	"fmt"
+	"strings"
+)
+
+// csvIntBits bit size of the integer go types, used when parsing csv values
+var csvIntBits = map[string]int{
+	"int": 64, "int8": 8, "int16": 16, "int32": 32, "int64": 64,
+	"uint": 64, "uint8": 8, "uint16": 16, "uint32": 32, "uint64": 64,
+}
+
+// csvFormat expression formatting value of the go type as a csv value, times are formatted as RFC 3339 and bytes as base64,
+// other types are formatted as json with the csvJSON helper of the api package
+func csvFormat(value, goType string) string {
+	switch {
+	case goType == "string":
+		return value
+	case csvIntBits[goType] > 0 && strings.HasPrefix(goType, "int"):
+		return fmt.Sprintf("strconv.FormatInt(int64(%s), 10)", value)
+	case csvIntBits[goType] > 0:
+		return fmt.Sprintf("strconv.FormatUint(uint64(%s), 10)", value)
+	case goType == "float32":
+		return fmt.Sprintf("strconv.FormatFloat(float64(%s), 'f', -1, 32)", value)
+	case goType == "float64":
+		return fmt.Sprintf("strconv.FormatFloat(%s, 'f', -1, 64)", value)
+	case goType == "bool":
+		return fmt.Sprintf("strconv.FormatBool(%s)", value)
+	case goType == "time.Time":
+		return fmt.Sprintf("%s.Format(time.RFC3339Nano)", value)
+	case goType == "[]byte":
+		return fmt.Sprintf("base64.StdEncoding.EncodeToString(%s)", value)
+	default:
+		return fmt.Sprintf("csvJSON(%s)", value)
+	}
+}
+
+// csvParse statements parsing the csv value src of the go type and the expression of the parsed value, parse errors are
+// returned with the invalidCSVValue helper of the api package. ok is false for the types parsed as json into the field.
+func csvParse(src, goType string) (code, value string, ok bool) {
+	var parse string
+	switch {
+	case goType == "string":
+		return "", src, true
+	case csvIntBits[goType] > 0 && strings.HasPrefix(goType, "int"):
+		parse = fmt.Sprintf("strconv.ParseInt(%s, 10, %d)", src, csvIntBits[goType])
+	case csvIntBits[goType] > 0:
+		parse = fmt.Sprintf("strconv.ParseUint(%s, 10, %d)", src, csvIntBits[goType])
+	case goType == "float32":
+		parse = fmt.Sprintf("strconv.ParseFloat(%s, 32)", src)
+	case goType == "float64":
+		parse = fmt.Sprintf("strconv.ParseFloat(%s, 64)", src)
+	case goType == "bool":
+		parse = fmt.Sprintf("strconv.ParseBool(%s)", src)
+	case goType == "time.Time":
+		parse = fmt.Sprintf("time.Parse(time.RFC3339Nano, %s)", src)
+	case goType == "[]byte":
+		parse = fmt.Sprintf("base64.StdEncoding.DecodeString(%s)", src)
+	default:
+		return "", "", false
+	}
+
+	value = "parsed"
+	if csvIntBits[goType] > 0 && goType != "int64" && goType != "uint64" || goType == "float32" {
+		value = fmt.Sprintf("%s(parsed)", goType)
+	}
+	return fmt.Sprintf("\n\tparsed, err := %s\n\tif err != nil {\n\t\treturn invalidCSVValue(name, %s)\n\t}", parse, src), value, true
+}
+
+// CSVFormatCode statement setting dst to the csv value of the field of the model src, NULL is exported as an empty value
+func (f *FieldInfo) CSVFormatCode(src, dst string) string {
+	value, isNull := nullableValue(fmt.Sprintf("%s.%s", src, f.GoFieldName), f.GoFieldType)
+	if strings.HasPrefix(value, "*") {
+		value = "(" + value + ")"
+	}
+	format := csvFormat(value, goBaseType(f.GoFieldType))
+
+	if isNull == "" {
+		return fmt.Sprintf("\n\t%s = %s", dst, format)
+	}
+	notNull := strings.Replace(strings.TrimPrefix(isNull, "!"), " == nil", " != nil", 1)
+	return fmt.Sprintf("\n\tif %s {\n\t\t%s = %s\n\t}", notNull, dst, format)
+}
+
+// CSVParseCode statements parsing the csv value src into the field of the model dst, name is the column of the value
+func (f *FieldInfo) CSVParseCode(src, dst string) string {
+	target := fmt.Sprintf("%s.%s", dst, f.GoFieldName)
+
+	parse, value, ok := csvParse(src, goBaseType(f.GoFieldType))
+	if !ok {
+		return fmt.Sprintf("\n\tif err := json.Unmarshal([]byte(%s), &%s); err != nil {\n\t\treturn invalidCSVValue(name, %s)\n\t}", src, target, src)
+	}
+
+	if n, ok := nullableTypes[f.GoFieldType]; ok {
+		return fmt.Sprintf("%s\n\t%s = %s", parse, target, fmt.Sprintf(n.Ctor, value, "true"))
+	}
+	if strings.HasPrefix(f.GoFieldType, "*") {
+		return fmt.Sprintf("%s\n\tv := %s\n\t%s = &v", parse, value, target)
+	}
+	return fmt.Sprintf("%s\n\t%s = %s", parse, target, value)
+}
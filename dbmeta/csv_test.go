@@ -0,0 +1,41 @@
+package dbmeta
+
+import (
+	"testing"
+)
+
+func Test_csvConversionCode(t *testing.T) {
+	tests := []struct {
+		field  *FieldInfo
+		format string
+		parse  string
+	}{
+		{&FieldInfo{GoFieldName: "Name", GoFieldType: "string"},
+			"\n\trow[0] = r.Name",
+			"\n\tm.Name = value"},
+		{&FieldInfo{GoFieldName: "ID", GoFieldType: "int32"},
+			"\n\trow[0] = strconv.FormatInt(int64(r.ID), 10)",
+			"\n\tparsed, err := strconv.ParseInt(value, 10, 32)\n\tif err != nil {\n\t\treturn invalidCSVValue(name, value)\n\t}\n\tm.ID = int32(parsed)"},
+		{&FieldInfo{GoFieldName: "Company", GoFieldType: "sql.NullString"},
+			"\n\tif r.Company.Valid {\n\t\trow[0] = r.Company.String\n\t}",
+			"\n\tm.Company = sql.NullString{String: value, Valid: true}"},
+		{&FieldInfo{GoFieldName: "Total", GoFieldType: "null.Float"},
+			"\n\tif r.Total.Valid {\n\t\trow[0] = strconv.FormatFloat(r.Total.Float64, 'f', -1, 64)\n\t}",
+			"\n\tparsed, err := strconv.ParseFloat(value, 64)\n\tif err != nil {\n\t\treturn invalidCSVValue(name, value)\n\t}\n\tm.Total = null.NewFloat(parsed, true)"},
+		{&FieldInfo{GoFieldName: "DeletedAt", GoFieldType: "*time.Time"},
+			"\n\tif r.DeletedAt != nil {\n\t\trow[0] = (*r.DeletedAt).Format(time.RFC3339Nano)\n\t}",
+			"\n\tparsed, err := time.Parse(time.RFC3339Nano, value)\n\tif err != nil {\n\t\treturn invalidCSVValue(name, value)\n\t}\n\tv := parsed\n\tm.DeletedAt = &v"},
+		{&FieldInfo{GoFieldName: "Data", GoFieldType: "interface{}"},
+			"\n\trow[0] = csvJSON(r.Data)",
+			"\n\tif err := json.Unmarshal([]byte(value), &m.Data); err != nil {\n\t\treturn invalidCSVValue(name, value)\n\t}"},
+	}
+
+	for _, tt := range tests {
+		if format := tt.field.CSVFormatCode("r", "row[0]"); format != tt.format {
+			t.Errorf("%s format: expect: %s, but got %s", tt.field.GoFieldType, tt.format, format)
+		}
+		if parse := tt.field.CSVParseCode("value", "m"); parse != tt.parse {
+			t.Errorf("%s parse: expect: %s, but got %s", tt.field.GoFieldType, tt.parse, parse)
+		}
+	}
+}
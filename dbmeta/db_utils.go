@@ -64,10 +64,23 @@ func GenerateDeleteSql(dbTable DbTableMeta) (string, error) {
 	return buf.String(), nil
 }
 
-// GenerateUpdateSql generate sql for a update
-func GenerateUpdateSql(dbTable DbTableMeta) (string, error) {
+// GenerateUpdateSql generate sql for a update, values are bound with ? and should be rebound for the driver in use.
+// skipColumns are left out of the update, e.g. a created_at column that is only set on insert
+func GenerateUpdateSql(dbTable DbTableMeta, skipColumns ...string) (string, error) {
+	return generateUpdateSql(dbTable, "", skipColumns)
+}
+
+// GenerateUpdateVersionedSql generate sql for a update using optimistic locking, the version column is incremented and
+// the row is only updated if the version matches. The version value is bound after the primary key values.
+func GenerateUpdateVersionedSql(dbTable DbTableMeta, versionColumn string, skipColumns ...string) (string, error) {
+	if versionColumn == "" {
+		return "", fmt.Errorf("table %s does not have a version column, cannot generate sql", dbTable.TableName())
+	}
+	return generateUpdateSql(dbTable, versionColumn, skipColumns)
+}
+
+func generateUpdateSql(dbTable DbTableMeta, versionColumn string, skipColumns []string) (string, error) {
 	primaryCnt := PrimaryKeyCount(dbTable)
-	// nonPrimaryCnt := len(dbTable.Columns()) - primaryCnt
 
 	if primaryCnt == 0 {
 		return "", fmt.Errorf("table %s does not have a primary key, cannot generate sql", dbTable.TableName())
@@ -76,14 +89,18 @@ func GenerateUpdateSql(dbTable DbTableMeta) (string, error) {
 	buf := bytes.Buffer{}
 	buf.WriteString(fmt.Sprintf("UPDATE %s set", dbTable.TableName()))
 
-	setCol := 1
+	setCol := 0
 	for _, col := range dbTable.Columns() {
-		if !col.IsPrimaryKey() {
-			if setCol != 1 {
+		if !col.IsPrimaryKey() && !containsName(skipColumns, col.Name()) {
+			if setCol != 0 {
 				buf.WriteString(",")
 			}
 
-			buf.WriteString(fmt.Sprintf(" %s = $%d", col.Name(), setCol))
+			if col.Name() == versionColumn {
+				buf.WriteString(fmt.Sprintf(" %s = %s + 1", col.Name(), col.Name()))
+			} else {
+				buf.WriteString(fmt.Sprintf(" %s = ?", col.Name()))
+			}
 			setCol++
 		}
 	}
@@ -92,9 +109,7 @@ func GenerateUpdateSql(dbTable DbTableMeta) (string, error) {
 	addedKey := 0
 	for _, col := range dbTable.Columns() {
 		if col.IsPrimaryKey() {
-			buf.WriteString(fmt.Sprintf(" %s = $%d", col.Name(), setCol))
-
-			setCol++
+			buf.WriteString(fmt.Sprintf(" %s = ?", col.Name()))
 			addedKey++
 
 			if addedKey < primaryCnt {
@@ -103,49 +118,119 @@ func GenerateUpdateSql(dbTable DbTableMeta) (string, error) {
 		}
 	}
 
+	if versionColumn != "" {
+		buf.WriteString(fmt.Sprintf(" AND %s = ?", versionColumn))
+	}
+
 	return buf.String(), nil
 }
 
-// GenerateInsertSql generate sql for a insert
-func GenerateInsertSql(dbTable DbTableMeta) (string, error) {
-	primaryCnt := PrimaryKeyCount(dbTable)
+func containsName(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
 
-	if primaryCnt == 0 {
+// GenerateSoftDeleteSql generate sql to soft delete a record by setting the soft delete column to the current time,
+// records that are already deleted are left untouched
+func GenerateSoftDeleteSql(dbTable DbTableMeta, softDeleteColumn string) (string, error) {
+	return generateSoftDeleteColumnSql(dbTable, softDeleteColumn, "CURRENT_TIMESTAMP", "IS NULL")
+}
+
+// GenerateRestoreSql generate sql to restore a soft deleted record by clearing the soft delete column
+func GenerateRestoreSql(dbTable DbTableMeta, softDeleteColumn string) (string, error) {
+	return generateSoftDeleteColumnSql(dbTable, softDeleteColumn, "NULL", "IS NOT NULL")
+}
+
+func generateSoftDeleteColumnSql(dbTable DbTableMeta, softDeleteColumn, value, condition string) (string, error) {
+	if softDeleteColumn == "" {
+		return "", fmt.Errorf("table %s does not have a soft delete column, cannot generate sql", dbTable.TableName())
+	}
+
+	primaryKeys := PrimaryKeyNames(dbTable)
+	if len(primaryKeys) == 0 {
 		return "", fmt.Errorf("table %s does not have a primary key, cannot generate sql", dbTable.TableName())
 	}
 
 	buf := bytes.Buffer{}
-	buf.WriteString(fmt.Sprintf("INSERT INTO %s (", dbTable.TableName()))
+	buf.WriteString(fmt.Sprintf("UPDATE %s set %s = %s WHERE", dbTable.TableName(), softDeleteColumn, value))
+	for _, name := range primaryKeys {
+		buf.WriteString(fmt.Sprintf(" %s = ? AND", name))
+	}
 
-	pastFirst := false
-	for _, col := range dbTable.Columns() {
-		if !col.IsAutoIncrement() {
-			if pastFirst {
-				buf.WriteString(", ")
-			}
+	buf.WriteString(fmt.Sprintf(" %s %s", softDeleteColumn, condition))
+	return buf.String(), nil
+}
 
-			buf.WriteString(fmt.Sprintf(" %s", col.Name()))
-			pastFirst = true
-		}
+// GenerateInsertSql generate sql for a insert, values are bound with ? and should be rebound for the driver in use
+func GenerateInsertSql(dbTable DbTableMeta) (string, error) {
+	columns, values, err := insertColumnsAndValues(dbTable, false)
+	if err != nil {
+		return "", err
 	}
-	buf.WriteString(") values ( ")
 
-	pastFirst = false
-	pos := 1
+	return fmt.Sprintf("INSERT INTO %s (%s) values ( %s )", dbTable.TableName(), columns, values), nil
+}
+
+// GenerateInsertReturningSql generate sql for a insert that returns the inserted row, used for postgres
+func GenerateInsertReturningSql(dbTable DbTableMeta) (string, error) {
+	insertSql, err := GenerateInsertSql(dbTable)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s RETURNING *", insertSql), nil
+}
+
+// GenerateInsertOutputSql generate sql for a insert that returns the inserted row, used for ms sql
+func GenerateInsertOutputSql(dbTable DbTableMeta) (string, error) {
+	columns, values, err := insertColumnsAndValues(dbTable, false)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("INSERT INTO %s (%s) OUTPUT INSERTED.* values ( %s )", dbTable.TableName(), columns, values), nil
+}
+
+// GenerateInsertWithKeysSql generate sql for a insert that sets the auto increment columns too, used to import records
+// keeping their keys
+func GenerateInsertWithKeysSql(dbTable DbTableMeta) (string, error) {
+	columns, values, err := insertColumnsAndValues(dbTable, true)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("INSERT INTO %s (%s) values ( %s )", dbTable.TableName(), columns, values), nil
+}
+
+func insertColumnsAndValues(dbTable DbTableMeta, autoIncrement bool) (columns string, values string, err error) {
+	primaryCnt := PrimaryKeyCount(dbTable)
+
+	if primaryCnt == 0 {
+		return "", "", fmt.Errorf("table %s does not have a primary key, cannot generate sql", dbTable.TableName())
+	}
+
+	colBuf := bytes.Buffer{}
+	valBuf := bytes.Buffer{}
+
+	pastFirst := false
 	for _, col := range dbTable.Columns() {
-		if !col.IsAutoIncrement() {
+		if autoIncrement || !col.IsAutoIncrement() {
 			if pastFirst {
-				buf.WriteString(", ")
+				colBuf.WriteString(", ")
+				valBuf.WriteString(", ")
 			}
 
-			buf.WriteString(fmt.Sprintf("$%d", pos))
-			pos++
+			colBuf.WriteString(fmt.Sprintf(" %s", col.Name()))
+			valBuf.WriteString("?")
 			pastFirst = true
 		}
 	}
 
-	buf.WriteString(" )")
-	return buf.String(), nil
+	return colBuf.String(), valBuf.String(), nil
 }
 
 // GenerateSelectOneSql generate sql for selecting one record
@@ -0,0 +1,158 @@
+package dbmeta
+
+import (
+	"testing"
+)
+
+type testColumn struct {
+	name          string
+	dbType        string
+	nullable      bool
+	primaryKey    bool
+	autoIncrement bool
+	length        int64
+	defaultValue  string
+	enumValues    []string
+	foreignKey    *ForeignKey
+}
+
+func (c *testColumn) Name() string               { return c.name }
+func (c *testColumn) String() string             { return c.name }
+func (c *testColumn) Nullable() bool             { return c.nullable }
+func (c *testColumn) DatabaseTypeName() string   { return c.dbType }
+func (c *testColumn) DatabaseTypePretty() string { return c.dbType }
+func (c *testColumn) Index() int                 { return 0 }
+func (c *testColumn) IsPrimaryKey() bool         { return c.primaryKey }
+func (c *testColumn) IsAutoIncrement() bool      { return c.autoIncrement }
+func (c *testColumn) IsArray() bool              { return false }
+func (c *testColumn) ColumnType() string         { return c.dbType }
+func (c *testColumn) Notes() string              { return "" }
+func (c *testColumn) ColumnLength() int64        { return c.length }
+func (c *testColumn) DefaultValue() string       { return c.defaultValue }
+func (c *testColumn) EnumValues() []string       { return c.enumValues }
+func (c *testColumn) ForeignKey() *ForeignKey    { return c.foreignKey }
+
+type testTable struct {
+	name    string
+	columns []*testColumn
+}
+
+func (t *testTable) Columns() []ColumnMeta {
+	cols := make([]ColumnMeta, len(t.columns))
+	for i, c := range t.columns {
+		cols[i] = c
+	}
+	return cols
+}
+func (t *testTable) SQLType() string     { return "sqlite3" }
+func (t *testTable) SQLDatabase() string { return "main" }
+func (t *testTable) TableName() string   { return t.name }
+func (t *testTable) DDL() string         { return "" }
+
+func albumsTable() *testTable {
+	return &testTable{
+		name: "albums",
+		columns: []*testColumn{
+			{name: "AlbumId", dbType: "INTEGER", primaryKey: true, autoIncrement: true},
+			{name: "Title", dbType: "NVARCHAR", length: 160},
+			{name: "ArtistId", dbType: "INTEGER"},
+		},
+	}
+}
+
+func Test_GenerateInsertSql(t *testing.T) {
+	tests := []struct {
+		name     string
+		generate func(DbTableMeta) (string, error)
+		expected string
+	}{
+		{"insert", GenerateInsertSql, "INSERT INTO albums ( Title,  ArtistId) values ( ?, ? )"},
+		{"returning", GenerateInsertReturningSql, "INSERT INTO albums ( Title,  ArtistId) values ( ?, ? ) RETURNING *"},
+		{"output", GenerateInsertOutputSql, "INSERT INTO albums ( Title,  ArtistId) OUTPUT INSERTED.* values ( ?, ? )"},
+		{"with keys", GenerateInsertWithKeysSql, "INSERT INTO albums ( AlbumId,  Title,  ArtistId) values ( ?, ?, ? )"},
+	}
+
+	for _, tt := range tests {
+		sql, err := tt.generate(albumsTable())
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+
+		if sql != tt.expected {
+			t.Errorf("%s: expect: %s, but got %s", tt.name, tt.expected, sql)
+		}
+	}
+}
+
+func Test_GenerateUpdateSql(t *testing.T) {
+	table := albumsTable()
+	table.columns = append(table.columns, &testColumn{name: "version", dbType: "INTEGER"})
+
+	sql, err := GenerateUpdateSql(table)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "UPDATE albums set Title = ?, ArtistId = ?, version = ? WHERE AlbumId = ?"
+	if sql != expected {
+		t.Errorf("expect: %s, but got %s", expected, sql)
+	}
+
+	sql, err = GenerateUpdateVersionedSql(table, "version")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected = "UPDATE albums set Title = ?, ArtistId = ?, version = version + 1 WHERE AlbumId = ? AND version = ?"
+	if sql != expected {
+		t.Errorf("expect: %s, but got %s", expected, sql)
+	}
+	table.columns = append(table.columns, &testColumn{name: "created_at", dbType: "DATETIME"})
+	sql, err = GenerateUpdateVersionedSql(table, "version", "created_at")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if sql != expected {
+		t.Errorf("expect skipped created_at: %s, but got %s", expected, sql)
+	}
+}
+
+func Test_GenerateSoftDeleteSql(t *testing.T) {
+	if err := LoadMappings("../template/mapping.json", false); err != nil {
+		t.Fatal(err)
+	}
+
+	table := albumsTable()
+	deletedAt := &testColumn{name: "deleted_at", dbType: "DATETIME", nullable: true}
+	table.columns = append(table.columns, deletedAt)
+
+	if col := findNamedColumn(table.Columns(), []string{"deleted_at"}, isSoftDeleteColumn); col != deletedAt {
+		t.Errorf("expect deleted_at to be the soft delete column, but got %v", col)
+	}
+
+	sql, err := GenerateSoftDeleteSql(table, "deleted_at")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "UPDATE albums set deleted_at = CURRENT_TIMESTAMP WHERE AlbumId = ? AND deleted_at IS NULL"
+	if sql != expected {
+		t.Errorf("expect: %s, but got %s", expected, sql)
+	}
+
+	sql, err = GenerateRestoreSql(table, "deleted_at")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected = "UPDATE albums set deleted_at = NULL WHERE AlbumId = ? AND deleted_at IS NOT NULL"
+	if sql != expected {
+		t.Errorf("expect: %s, but got %s", expected, sql)
+	}
+
+	deletedAt.nullable = false
+	if col := findNamedColumn(table.Columns(), []string{"deleted_at"}, isSoftDeleteColumn); col != nil {
+		t.Errorf("expect non nullable deleted_at to be ignored, but got %v", col)
+	}
+}
@@ -0,0 +1,235 @@
+package dbmeta
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// diagramRenderer renders an entity relationship diagram of the tables of a snapshot
+type diagramRenderer func(s *SchemaSnapshot) string
+
+var diagramFuncs = map[string]diagramRenderer{
+	"mermaid":  mermaidERDiagram,
+	"plantuml": plantUMLERDiagram,
+	"dot":      dotERDiagram,
+}
+
+var diagramInvalidChars = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+// RenderERDiagram entity relationship diagram of the tables of the snapshot in the format [mermaid | plantuml | dot],
+// foreign keys referencing tables that are not in the snapshot are left out
+func RenderERDiagram(s *SchemaSnapshot, format string) (string, error) {
+	renderer, ok := diagramFuncs[strings.ToLower(format)]
+	if !ok {
+		return "", fmt.Errorf("unsupported diagram format %s", format)
+	}
+	return renderer(s), nil
+}
+
+// Neighbours snapshot of the tables and the tables within hops foreign keys of them, following foreign keys in both
+// directions. Tables that are not in the snapshot are returned as an error.
+func (s *SchemaSnapshot) Neighbours(tables []string, hops int) (*SchemaSnapshot, error) {
+	selected := make(map[string]bool)
+	for _, name := range tables {
+		if s.Table(name) == nil {
+			return nil, fmt.Errorf("table %s not found", name)
+		}
+		selected[name] = true
+	}
+
+	for i := 0; i < hops; i++ {
+		var found []string
+		for _, t := range s.Tables {
+			for _, fk := range t.ForeignKeys {
+				if selected[t.Name] && !selected[fk.RefTable] && s.Table(fk.RefTable) != nil {
+					found = append(found, fk.RefTable)
+				}
+				if selected[fk.RefTable] && !selected[t.Name] {
+					found = append(found, t.Name)
+				}
+			}
+		}
+		if len(found) == 0 {
+			break
+		}
+		for _, name := range found {
+			selected[name] = true
+		}
+	}
+
+	subset := &SchemaSnapshot{SQLType: s.SQLType}
+	for _, t := range s.Tables {
+		if selected[t.Name] {
+			subset.Tables = append(subset.Tables, t)
+		}
+	}
+	return subset, nil
+}
+
+// diagramRelations foreign keys of the snapshot between tables of the snapshot, sorted by table and column
+func diagramRelations(s *SchemaSnapshot) (relations []*diagramRelation) {
+	for _, t := range s.Tables {
+		for _, fk := range t.ForeignKeys {
+			if s.Table(fk.RefTable) == nil {
+				continue
+			}
+
+			c := t.column(fk.Column)
+			relations = append(relations, &diagramRelation{Table: t, ForeignKey: fk, Optional: c != nil && c.Nullable})
+		}
+	}
+
+	sort.SliceStable(relations, func(i, j int) bool {
+		if relations[i].Table.Name != relations[j].Table.Name {
+			return relations[i].Table.Name < relations[j].Table.Name
+		}
+		return relations[i].ForeignKey.Column < relations[j].ForeignKey.Column
+	})
+	return relations
+}
+
+// diagramRelation foreign key of a table, the referenced row is optional when the foreign key column is nullable
+type diagramRelation struct {
+	Table      *TableSchema
+	ForeignKey *ForeignKeySchema
+	Optional   bool
+}
+
+// diagramKeys PK and FK when the column is part of the primary key or a foreign key of the table
+func diagramKeys(t *TableSchema, c *ColumnSchema) []string {
+	var keys []string
+	if containsString(t.PrimaryKey, c.Name) {
+		keys = append(keys, "PK")
+	}
+	for _, fk := range t.ForeignKeys {
+		if fk.Column == c.Name {
+			keys = append(keys, "FK")
+			break
+		}
+	}
+	return keys
+}
+
+// diagramName name usable as an identifier in the diagram formats, the length and precision of a type are dropped
+func diagramName(name string) string {
+	if i := strings.Index(name, "("); i > 0 {
+		name = name[:i]
+	}
+	return strings.Trim(diagramInvalidChars.ReplaceAllString(strings.TrimSpace(name), "_"), "_")
+}
+
+func mermaidERDiagram(s *SchemaSnapshot) string {
+	buf := bytes.Buffer{}
+	buf.WriteString("erDiagram\n")
+
+	for _, t := range s.Tables {
+		buf.WriteString(fmt.Sprintf("    %s {\n", diagramName(t.Name)))
+		for _, c := range t.Columns {
+			line := fmt.Sprintf("        %s %s", diagramName(c.Type), diagramName(c.Name))
+			if keys := diagramKeys(t, c); len(keys) > 0 {
+				line += " " + strings.Join(keys, ", ")
+			}
+			if c.Comment != "" {
+				line += fmt.Sprintf(" %q", strings.Replace(c.Comment, `"`, "'", -1))
+			}
+			buf.WriteString(line + "\n")
+		}
+		buf.WriteString("    }\n")
+	}
+
+	for _, r := range diagramRelations(s) {
+		relationship := "||--o{"
+		if r.Optional {
+			relationship = "|o--o{"
+		}
+		buf.WriteString(fmt.Sprintf("    %s %s %s : %q\n", diagramName(r.ForeignKey.RefTable), relationship, diagramName(r.Table.Name), r.ForeignKey.Column))
+	}
+	return buf.String()
+}
+
+func plantUMLERDiagram(s *SchemaSnapshot) string {
+	buf := bytes.Buffer{}
+	buf.WriteString("@startuml\nhide circle\nskinparam linetype ortho\n\n")
+
+	for _, t := range s.Tables {
+		buf.WriteString(fmt.Sprintf("entity %q as %s {\n", t.Name, diagramName(t.Name)))
+
+		// primary key columns are listed above the separator, mandatory columns are marked with *
+		var keyColumns, columns []string
+		for _, c := range t.Columns {
+			line := "  "
+			if !c.Nullable {
+				line += "* "
+			}
+			line += fmt.Sprintf("%s : %s", c.Name, c.Type)
+			for _, key := range diagramKeys(t, c) {
+				line += fmt.Sprintf(" <<%s>>", key)
+			}
+
+			if containsString(t.PrimaryKey, c.Name) {
+				keyColumns = append(keyColumns, line)
+			} else {
+				columns = append(columns, line)
+			}
+		}
+
+		for _, line := range keyColumns {
+			buf.WriteString(line + "\n")
+		}
+		buf.WriteString("  --\n")
+		for _, line := range columns {
+			buf.WriteString(line + "\n")
+		}
+		buf.WriteString("}\n\n")
+	}
+
+	for _, r := range diagramRelations(s) {
+		relationship := "||--o{"
+		if r.Optional {
+			relationship = "|o--o{"
+		}
+		buf.WriteString(fmt.Sprintf("%s %s %s : %s\n", diagramName(r.ForeignKey.RefTable), relationship, diagramName(r.Table.Name), r.ForeignKey.Column))
+	}
+	buf.WriteString("@enduml\n")
+	return buf.String()
+}
+
+func dotERDiagram(s *SchemaSnapshot) string {
+	buf := bytes.Buffer{}
+	buf.WriteString("digraph schema {\n")
+	buf.WriteString("    graph [rankdir=LR];\n")
+	buf.WriteString("    node [shape=plaintext, fontname=\"Helvetica\"];\n")
+	buf.WriteString("    edge [arrowhead=crow, arrowtail=tee, dir=both];\n\n")
+
+	for _, t := range s.Tables {
+		buf.WriteString(fmt.Sprintf("    %q [label=<<table border=\"0\" cellborder=\"1\" cellspacing=\"0\">\n", t.Name))
+		buf.WriteString(fmt.Sprintf("        <tr><td bgcolor=\"lightgrey\" colspan=\"3\"><b>%s</b></td></tr>\n", html.EscapeString(t.Name)))
+		for _, c := range t.Columns {
+			name := html.EscapeString(c.Name)
+			if containsString(t.PrimaryKey, c.Name) {
+				name = fmt.Sprintf("<u>%s</u>", name)
+			}
+			buf.WriteString(fmt.Sprintf("        <tr><td port=\"%s\" align=\"left\">%s</td><td align=\"left\">%s</td><td align=\"left\">%s</td></tr>\n",
+				html.EscapeString(c.Name), name, html.EscapeString(c.Type), strings.Join(diagramKeys(t, c), ", ")))
+		}
+		buf.WriteString("    </table>>];\n")
+	}
+
+	relations := diagramRelations(s)
+	if len(relations) > 0 {
+		buf.WriteString("\n")
+	}
+	for _, r := range relations {
+		style := ""
+		if r.Optional {
+			style = " [style=dashed]"
+		}
+		buf.WriteString(fmt.Sprintf("    %q:%q -> %q:%q%s;\n", r.ForeignKey.RefTable, r.ForeignKey.RefColumn, r.Table.Name, r.ForeignKey.Column, style))
+	}
+	buf.WriteString("}\n")
+	return buf.String()
+}
@@ -0,0 +1,114 @@
+package dbmeta
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_RenderERDiagram(t *testing.T) {
+	s, _ := migrateTestSnapshots("mysql")
+	s.Tables[0].Columns[1].Comment = `the "name"`
+
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{"mermaid", `erDiagram
+    artists {
+        int id PK
+        varchar name "the 'name'"
+    }
+    albums {
+        int id PK
+        int artist_id FK
+    }
+    artists ||--o{ albums : "artist_id"
+`},
+		{"plantuml", `@startuml
+hide circle
+skinparam linetype ortho
+
+entity "artists" as artists {
+  * id : int <<PK>>
+  --
+  name : varchar(120)
+}
+
+entity "albums" as albums {
+  * id : int <<PK>>
+  --
+  * artist_id : int <<FK>>
+}
+
+artists ||--o{ albums : artist_id
+@enduml
+`},
+	}
+
+	for _, tt := range tests {
+		diagram, err := RenderERDiagram(s, tt.format)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.format, err)
+			continue
+		}
+		if diagram != tt.expected {
+			t.Errorf("%s: expect: %s, but got %s", tt.format, tt.expected, diagram)
+		}
+	}
+
+	diagram, err := RenderERDiagram(s, "dot")
+	if err != nil {
+		t.Fatalf("dot: unexpected error: %v", err)
+	}
+	edge := `"artists":"id" -> "albums":"artist_id";`
+	if !strings.HasPrefix(diagram, "digraph schema {") || !strings.Contains(diagram, edge) {
+		t.Errorf("dot: expect a digraph with the edge %s, but got %s", edge, diagram)
+	}
+
+	if _, err := RenderERDiagram(s, "svg"); err == nil {
+		t.Errorf("svg: expect an error")
+	}
+}
+
+func Test_Neighbours(t *testing.T) {
+	s := &SchemaSnapshot{SQLType: "postgres", Tables: []*TableSchema{
+		{Name: "albums", ForeignKeys: []*ForeignKeySchema{{Column: "artist_id", RefTable: "artists", RefColumn: "id"}}},
+		{Name: "artists"},
+		{Name: "genres"},
+		{Name: "tracks", ForeignKeys: []*ForeignKeySchema{
+			{Column: "album_id", RefTable: "albums", RefColumn: "id"},
+			{Column: "genre_id", RefTable: "genres", RefColumn: "id"},
+		}},
+	}}
+
+	tests := []struct {
+		tables   []string
+		hops     int
+		expected string
+	}{
+		{[]string{"albums"}, 0, "albums"},
+		{[]string{"albums"}, 1, "albums,artists,tracks"},
+		{[]string{"albums"}, 2, "albums,artists,genres,tracks"},
+		{[]string{"artists", "genres"}, 1, "albums,artists,genres,tracks"},
+	}
+
+	for _, tt := range tests {
+		subset, err := s.Neighbours(tt.tables, tt.hops)
+		if err != nil {
+			t.Errorf("%v: unexpected error: %v", tt.tables, err)
+			continue
+		}
+
+		var names []string
+		for _, table := range subset.Tables {
+			names = append(names, table.Name)
+		}
+		if strings.Join(names, ",") != tt.expected {
+			t.Errorf("%v %d hops: expect: %s, but got %s", tt.tables, tt.hops, tt.expected, strings.Join(names, ","))
+		}
+	}
+
+	if _, err := s.Neighbours([]string{"playlists"}, 1); err == nil {
+		t.Errorf("playlists: expect an error")
+	}
+}
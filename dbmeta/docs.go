@@ -0,0 +1,112 @@
+package dbmeta
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DocsTable page of a table in the data dictionary
+type DocsTable struct {
+	Name         string
+	Comment      string
+	FileName     string
+	Columns      []*DocsColumn
+	PrimaryKey   []string
+	Indexes      []*IndexSchema
+	ForeignKeys  []*ForeignKeySchema
+	ReferencedBy []*DocsReference
+	DDL          string
+}
+
+// DocsColumn column of a table in the data dictionary, Keys lists PK, FK and UK when the column is part of the primary key, a
+// foreign key or a single column unique index
+type DocsColumn struct {
+	Name          string
+	Type          string
+	Nullable      bool
+	Default       string
+	AutoIncrement bool
+	Keys          []string
+	References    string
+	Comment       string
+}
+
+// DocsReference foreign key of another table referencing the table
+type DocsReference struct {
+	Table     string
+	Column    string
+	RefColumn string
+}
+
+// NewDocsTables pages of the data dictionary of the tables of the snapshot, the ddl of each table is the create table, index
+// and foreign key statements of the dialect of the snapshot
+func NewDocsTables(s *SchemaSnapshot) []*DocsTable {
+	var tables []*DocsTable
+
+	for _, t := range s.Tables {
+		table := &DocsTable{
+			Name:        t.Name,
+			Comment:     t.Comment,
+			FileName:    fmt.Sprintf("%s.md", t.Name),
+			PrimaryKey:  t.PrimaryKey,
+			Indexes:     t.Indexes,
+			ForeignKeys: t.ForeignKeys,
+			DDL:         CreateSchemaDDL(&SchemaSnapshot{SQLType: s.SQLType, Tables: []*TableSchema{t}}),
+		}
+
+		for _, c := range t.Columns {
+			column := &DocsColumn{
+				Name:          c.Name,
+				Type:          c.Type,
+				Nullable:      c.Nullable,
+				Default:       c.Default,
+				AutoIncrement: c.AutoIncrement,
+				Comment:       c.Comment,
+			}
+
+			if containsString(t.PrimaryKey, c.Name) {
+				column.Keys = append(column.Keys, "PK")
+			}
+			for _, fk := range t.ForeignKeys {
+				if fk.Column == c.Name {
+					column.Keys = append(column.Keys, "FK")
+					column.References = fmt.Sprintf("%s.%s", fk.RefTable, fk.RefColumn)
+					break
+				}
+			}
+			for _, idx := range t.Indexes {
+				if idx.Unique && len(idx.Columns) == 1 && idx.Columns[0] == c.Name {
+					column.Keys = append(column.Keys, "UK")
+					break
+				}
+			}
+			table.Columns = append(table.Columns, column)
+		}
+
+		for _, other := range s.Tables {
+			for _, fk := range other.ForeignKeys {
+				if fk.RefTable == t.Name {
+					table.ReferencedBy = append(table.ReferencedBy, &DocsReference{Table: other.Name, Column: fk.Column, RefColumn: fk.RefColumn})
+				}
+			}
+		}
+		tables = append(tables, table)
+	}
+	return tables
+}
+
+// markdownCell text escaped for a cell of a markdown table
+func markdownCell(s string) string {
+	s = strings.Replace(s, "|", `\|`, -1)
+	s = strings.Replace(s, "\r\n", "<br>", -1)
+	return strings.Replace(s, "\n", "<br>", -1)
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+package dbmeta
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_NewDocsTables(t *testing.T) {
+	s, _ := migrateTestSnapshots("postgres")
+	s.Tables[0].Indexes = []*IndexSchema{{Name: "uq_name", Columns: []string{"name"}, Unique: true}}
+	s.Tables[0].Columns[1].Comment = "name | alias"
+
+	tables := NewDocsTables(s)
+	if len(tables) != 2 {
+		t.Fatalf("expect: 2 tables, but got %d", len(tables))
+	}
+
+	artists, albums := tables[0], tables[1]
+	tests := []struct {
+		name     string
+		expected string
+		actual   string
+	}{
+		{"artists.id keys", "PK", strings.Join(artists.Columns[0].Keys, ", ")},
+		{"artists.name keys", "UK", strings.Join(artists.Columns[1].Keys, ", ")},
+		{"albums.artist_id keys", "FK", strings.Join(albums.Columns[1].Keys, ", ")},
+		{"albums.artist_id references", "artists.id", albums.Columns[1].References},
+		{"artists referenced by", "albums.artist_id", artists.ReferencedBy[0].Table + "." + artists.ReferencedBy[0].Column},
+		{"artists comment cell", `name \| alias`, markdownCell(artists.Columns[1].Comment)},
+	}
+
+	for _, tt := range tests {
+		if tt.actual != tt.expected {
+			t.Errorf("%s: expect: %s, but got %s", tt.name, tt.expected, tt.actual)
+		}
+	}
+
+	if !strings.HasPrefix(albums.DDL, "CREATE TABLE \"albums\" (") {
+		t.Errorf("albums ddl: expect a create table statement, but got %s", albums.DDL)
+	}
+}
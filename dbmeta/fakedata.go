@@ -0,0 +1,217 @@
+package dbmeta
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/bxcodec/faker/v3"
+)
+
+// FakeDataRule generator of the fake values of the columns the rule matches, used for the json samples, the api examples and
+// the seed data. A rule matches a column when the Column and SQLType expressions (case insensitive, empty matches any) match
+// the column name and database type and the go type of the column is one of GoTypes (empty matches any). Generator names a
+// generator in FakeDataGenerators, without a generator a value is picked from Values.
+type FakeDataRule struct {
+	Column    string   `json:"column"`
+	SQLType   string   `json:"sql_type"`
+	GoTypes   []string `json:"go_types"`
+	Generator string   `json:"generator"`
+	Values    []string `json:"values"`
+
+	columnRe  *regexp.Regexp
+	sqlTypeRe *regexp.Regexp
+}
+
+// FakeDataGenerators generators of fake values by name, rules in the mapping file may use any of them
+var FakeDataGenerators = map[string]func() interface{}{
+	"email":        func() interface{} { return fakeEmail() },
+	"phone":        func() interface{} { return faker.Phonenumber() },
+	"name":         func() interface{} { return faker.FirstName() + " " + faker.LastName() },
+	"first_name":   func() interface{} { return faker.FirstName() },
+	"last_name":    func() interface{} { return faker.LastName() },
+	"username":     func() interface{} { return faker.Username() },
+	"url":          func() interface{} { return faker.URL() },
+	"domain":       func() interface{} { return faker.DomainName() },
+	"country":      func() interface{} { return fakeCountries[rand.Intn(len(fakeCountries))].Name },
+	"country_code": func() interface{} { return fakeCountries[rand.Intn(len(fakeCountries))].Code },
+	"city":         func() interface{} { return fakeCities[rand.Intn(len(fakeCities))] },
+	"address":      func() interface{} { return fmt.Sprintf("%d %s Street", 1+rand.Intn(999), faker.LastName()) },
+	"postal_code":  func() interface{} { return fmt.Sprintf("%05d", rand.Intn(100000)) },
+	"timestamp":    func() interface{} { return fakeTimestamp() },
+	"price":        func() interface{} { return float64(100+rand.Intn(99900)) / 100 },
+	"uuid":         func() interface{} { return fakeUUID() },
+	"ipv4":         func() interface{} { return faker.IPv4() },
+	"ipv6":         func() interface{} { return faker.IPv6() },
+	"word":         func() interface{} { return faker.Word() },
+	"sentence":     func() interface{} { return faker.Sentence() },
+	"paragraph":    func() interface{} { return faker.Paragraph() },
+}
+
+// defaultFakeDataRules rules applied after the rules of the mapping files, the first matching rule is used
+var defaultFakeDataRules = []*FakeDataRule{
+	{SQLType: `^(uuid|uniqueidentifier)$`, Generator: "uuid"},
+	{SQLType: `^inet$`, Generator: "ipv4"},
+	{Column: `uuid|guid`, GoTypes: []string{"string"}, Generator: "uuid"},
+	{Column: `e_?mail`, GoTypes: []string{"string"}, Generator: "email"},
+	{Column: `phone|mobile|fax`, GoTypes: []string{"string"}, Generator: "phone"},
+	{Column: `^(first_?name|given_?name)$`, GoTypes: []string{"string"}, Generator: "first_name"},
+	{Column: `^(last_?name|surname|family_?name)$`, GoTypes: []string{"string"}, Generator: "last_name"},
+	{Column: `^(user_?name|login)$`, GoTypes: []string{"string"}, Generator: "username"},
+	{Column: `^(full_?|display_?|contact_?|customer_?)?name$`, GoTypes: []string{"string"}, Generator: "name"},
+	{Column: `url|website|homepage`, GoTypes: []string{"string"}, Generator: "url"},
+	{Column: `country_?code`, GoTypes: []string{"string"}, Generator: "country_code"},
+	{Column: `country`, GoTypes: []string{"string"}, Generator: "country"},
+	{Column: `city`, GoTypes: []string{"string"}, Generator: "city"},
+	{Column: `^ip$|^ip_|_ip$|ip_?address`, GoTypes: []string{"string"}, Generator: "ipv4"},
+	{Column: `address`, GoTypes: []string{"string"}, Generator: "address"},
+	{Column: `postal_?code|zip`, GoTypes: []string{"string"}, Generator: "postal_code"},
+	{Column: `_at$|_on$|_time$|date`, GoTypes: []string{"time.Time"}, Generator: "timestamp"},
+	{Column: `price|amount|cost|total|fee`, GoTypes: []string{"float32", "float64"}, Generator: "price"},
+}
+
+var fakeDataRules = defaultFakeDataRules
+
+type fakeCountry struct {
+	Name string
+	Code string
+}
+
+var fakeCountries = []fakeCountry{
+	{"Argentina", "AR"}, {"Australia", "AU"}, {"Brazil", "BR"}, {"Canada", "CA"}, {"China", "CN"}, {"France", "FR"},
+	{"Germany", "DE"}, {"India", "IN"}, {"Italy", "IT"}, {"Japan", "JP"}, {"Mexico", "MX"}, {"Netherlands", "NL"},
+	{"New Zealand", "NZ"}, {"Norway", "NO"}, {"Poland", "PL"}, {"South Africa", "ZA"}, {"Spain", "ES"}, {"Sweden", "SE"},
+	{"United Kingdom", "GB"}, {"United States", "US"},
+}
+
+var fakeCities = []string{
+	"Amsterdam", "Auckland", "Berlin", "Buenos Aires", "Chicago", "Dublin", "Lisbon", "London", "Madrid", "Melbourne",
+	"Mumbai", "New York", "Oslo", "Paris", "Rome", "San Francisco", "Sao Paulo", "Stockholm", "Tokyo", "Toronto",
+}
+
+// AddFakeDataRules compiles the rules and adds them ahead of the rules already loaded
+func AddFakeDataRules(rules []*FakeDataRule) error {
+	for _, r := range rules {
+		if err := r.compile(); err != nil {
+			return err
+		}
+	}
+	fakeDataRules = append(append([]*FakeDataRule{}, rules...), fakeDataRules...)
+	return nil
+}
+
+func (r *FakeDataRule) compile() (err error) {
+	if r.Generator == "" && len(r.Values) == 0 {
+		return fmt.Errorf("fake data rule %q has neither a generator nor values", r.Column)
+	}
+	if _, ok := FakeDataGenerators[r.Generator]; r.Generator != "" && !ok {
+		return fmt.Errorf("fake data rule %q has an unknown generator %s", r.Column, r.Generator)
+	}
+
+	if r.Column != "" {
+		if r.columnRe, err = regexp.Compile("(?i)" + r.Column); err != nil {
+			return fmt.Errorf("fake data rule %q has an invalid column expression: %v", r.Column, err)
+		}
+	}
+	if r.SQLType != "" {
+		if r.sqlTypeRe, err = regexp.Compile("(?i)" + r.SQLType); err != nil {
+			return fmt.Errorf("fake data rule %q has an invalid sql type expression: %v", r.Column, err)
+		}
+	}
+	return nil
+}
+
+func (r *FakeDataRule) matches(col ColumnMeta, goType string) bool {
+	if r.columnRe == nil && r.sqlTypeRe == nil && r.compile() != nil {
+		return false
+	}
+	if r.columnRe != nil && !r.columnRe.MatchString(col.Name()) {
+		return false
+	}
+	if r.sqlTypeRe != nil && !r.sqlTypeRe.MatchString(col.DatabaseTypeName()) {
+		return false
+	}
+	return len(r.GoTypes) == 0 || containsString(r.GoTypes, goType)
+}
+
+// value generates a fake value, strings are truncated to the length
+func (r *FakeDataRule) value(length int64) interface{} {
+	var value interface{}
+	if r.Generator != "" {
+		value = FakeDataGenerators[r.Generator]()
+	} else {
+		value = r.Values[rand.Intn(len(r.Values))]
+	}
+
+	if s, ok := value.(string); ok {
+		return fitLength(s, length)
+	}
+	return value
+}
+
+// fakeRuleValue fake value of the field from the first rule matching the column, false when no rule matches
+func fakeRuleValue(f *FieldInfo) (interface{}, bool) {
+	goType := goBaseType(f.GoFieldType)
+	if f.SqlMapping != nil {
+		goType = f.SqlMapping.GoType
+	}
+
+	for _, r := range fakeDataRules {
+		if r.matches(f.ColumnMeta, goType) {
+			return r.value(f.ColumnMeta.ColumnLength()), true
+		}
+	}
+	return nil, false
+}
+
+// setSampleValues replaces the values of the fake instance with the values of the rules matching the columns and truncates
+// the strings to the column length
+func setSampleValues(instance interface{}, fields []*FieldInfo) {
+	v := reflect.ValueOf(instance).Elem()
+
+	for _, f := range fields {
+		field := v.FieldByName(f.GoFieldName)
+		if !field.IsValid() || f.ColumnMeta == nil {
+			continue
+		}
+
+		if value, ok := fakeRuleValue(f); ok {
+			rv := reflect.ValueOf(value)
+			switch {
+			case rv.Type() == field.Type():
+				field.Set(rv)
+			case isFloatKind(rv.Kind()) && isFloatKind(field.Kind()):
+				field.SetFloat(rv.Float())
+			}
+		}
+
+		if field.Kind() == reflect.String {
+			field.SetString(fitLength(field.String(), f.ColumnMeta.ColumnLength()))
+		}
+	}
+}
+
+func isFloatKind(k reflect.Kind) bool {
+	return k == reflect.Float32 || k == reflect.Float64
+}
+
+// fakeTimestamp time within a year after the sample time, truncated to the second
+func fakeTimestamp() time.Time {
+	return sampleTime.Add(time.Duration(rand.Int63n(int64(365 * 24 * time.Hour)))).Truncate(time.Second)
+}
+
+func fakeEmail() string {
+	return strings.ToLower(fmt.Sprintf("%s.%s@example.com", faker.FirstName(), faker.LastName()))
+}
+
+// fakeUUID random version 4 uuid, generated from math/rand so the samples are the same each time the code is generated
+func fakeUUID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
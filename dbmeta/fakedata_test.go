@@ -0,0 +1,73 @@
+package dbmeta
+
+import (
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func Test_FakeRuleValue(t *testing.T) {
+	tests := []struct {
+		column  *testColumn
+		goType  string
+		pattern string
+	}{
+		{&testColumn{name: "Email", dbType: "VARCHAR", length: 60}, "string", `^[a-z]+\.[a-z]+@example\.com$`},
+		{&testColumn{name: "contact_email", dbType: "VARCHAR", length: 10}, "string", `^.{1,10}$`},
+		{&testColumn{name: "country", dbType: "VARCHAR", length: 40}, "string", `^[A-Z][a-zA-Z ]+$`},
+		{&testColumn{name: "country_code", dbType: "CHAR", length: 2}, "string", `^[A-Z]{2}$`},
+		{&testColumn{name: "user_uuid", dbType: "VARCHAR", length: 36}, "string", `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`},
+		{&testColumn{name: "token", dbType: "uuid"}, "string", `^[0-9a-f]{8}-`},
+		{&testColumn{name: "ip_address", dbType: "VARCHAR", length: 45}, "string", `^\d+\.\d+\.\d+\.\d+$`},
+		{&testColumn{name: "unit_price", dbType: "NUMERIC"}, "float64", `^\d+(\.\d{1,2})?$`},
+	}
+
+	for _, tt := range tests {
+		f := &FieldInfo{GoFieldType: tt.goType, ColumnMeta: tt.column}
+		value, ok := fakeRuleValue(f)
+		if !ok {
+			t.Errorf("%s: expect a rule to match", tt.column.name)
+			continue
+		}
+
+		actual := ""
+		switch v := value.(type) {
+		case string:
+			actual = v
+		case float64:
+			actual = strconv.FormatFloat(v, 'f', -1, 64)
+		}
+		if !regexp.MustCompile(tt.pattern).MatchString(actual) {
+			t.Errorf("%s: expect: %s, but got %v", tt.column.name, tt.pattern, value)
+		}
+	}
+
+	created := &FieldInfo{GoFieldType: "time.Time", ColumnMeta: &testColumn{name: "created_at", dbType: "DATETIME"}}
+	if value, ok := fakeRuleValue(created); !ok || value.(time.Time).Before(sampleTime) {
+		t.Errorf("created_at: expect a time after %v, but got %v", sampleTime, value)
+	}
+
+	title := &FieldInfo{GoFieldType: "string", ColumnMeta: &testColumn{name: "title", dbType: "VARCHAR"}}
+	if value, ok := fakeRuleValue(title); ok {
+		t.Errorf("title: expect no rule to match, but got %v", value)
+	}
+}
+
+func Test_AddFakeDataRules(t *testing.T) {
+	defer func(rules []*FakeDataRule) { fakeDataRules = rules }(fakeDataRules)
+
+	if err := AddFakeDataRules([]*FakeDataRule{{Column: "^title$", Generator: "missing"}}); err == nil {
+		t.Errorf("unknown generator: expect an error")
+	}
+	if err := AddFakeDataRules([]*FakeDataRule{{Column: "^(title|email)$", Values: []string{"Mr", "Ms"}}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, name := range []string{"title", "email"} {
+		f := &FieldInfo{GoFieldType: "string", ColumnMeta: &testColumn{name: name, dbType: "VARCHAR"}}
+		if value, _ := fakeRuleValue(f); value != "Mr" && value != "Ms" {
+			t.Errorf("%s: expect: Mr or Ms, but got %v", name, value)
+		}
+	}
+}
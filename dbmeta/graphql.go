@@ -0,0 +1,275 @@
+package dbmeta
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/jinzhu/inflection"
+)
+
+// graphqlScalar graphql scalar of a go type and the go type graph-gophers/graphql-go binds the scalar to
+type graphqlScalar struct {
+	Name   string
+	GoType string
+	// Base integer type of a GoType declared in the generated graphql package, values are converted through it
+	Base string
+}
+
+// graphqlScalars scalars of the go types of the model fields. Integers that fit 32 bits are sent as the graphql Int, larger
+// integers as the Int64 and UInt64 scalars of the generated package, which are strings on the wire and range checked when
+// they are read.
+var graphqlScalars = map[string]graphqlScalar{
+	"int":       {"Int64", "Int64", "int64"},
+	"int8":      {"Int", "int32", ""},
+	"int16":     {"Int", "int32", ""},
+	"int32":     {"Int", "int32", ""},
+	"int64":     {"Int64", "Int64", "int64"},
+	"uint":      {"UInt64", "UInt64", "uint64"},
+	"uint8":     {"Int", "int32", ""},
+	"uint16":    {"Int", "int32", ""},
+	"uint32":    {"Int64", "UInt32", "uint32"},
+	"uint64":    {"UInt64", "UInt64", "uint64"},
+	"float32":   {"Float", "float64", ""},
+	"float64":   {"Float", "float64", ""},
+	"string":    {"String", "string", ""},
+	"[]byte":    {"String", "string", ""},
+	"bool":      {"Boolean", "bool", ""},
+	"time.Time": {"Time", "graphql.Time", ""},
+}
+
+// graphqlName lower camel case of a go name, the leading initialism is lower cased: ID is id and URLPath is urlPath
+func graphqlName(goName string) string {
+	runes := []rune(goName)
+	for i := 0; i < len(runes) && unicode.IsUpper(runes[i]); i++ {
+		if i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			break
+		}
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
+
+// graphqlScalarOf scalar of a field, false when the go type of the field has no graphql scalar
+func graphqlScalarOf(f *FieldInfo) (graphqlScalar, bool) {
+	scalar, ok := graphqlScalars[goBaseType(f.GoFieldType)]
+	return scalar, ok
+}
+
+// isNullableGoType reports if the go type holds NULL as a sql or guregu nullable type or a pointer
+func isNullableGoType(goType string) bool {
+	_, ok := nullableTypes[goType]
+	return ok || strings.HasPrefix(goType, "*")
+}
+
+// nullableValue expressions reading the value of a field of the go type and reporting if it is NULL, isNull is empty when the
+// go type cannot hold NULL
+func nullableValue(field, goType string) (value, isNull string) {
+	if n, ok := nullableTypes[goType]; ok {
+		return fmt.Sprintf("%s.%s", field, n.Value), fmt.Sprintf("!%s.Valid", field)
+	}
+	if strings.HasPrefix(goType, "*") {
+		return "*" + field, field + " == nil"
+	}
+	return field, ""
+}
+
+// toGraphqlValue expression converting value of the go type to the go type of the graphql scalar
+func toGraphqlValue(value, goType string, scalar graphqlScalar) (string, bool) {
+	if scalar.GoType == "graphql.Time" {
+		return fmt.Sprintf("graphql.Time{Time: %s}", value), true
+	}
+	if scalar.Base != "" {
+		value, ok := convertGoValue(value, goType, scalar.Base)
+		return fmt.Sprintf("%s(%s)", scalar.GoType, value), ok
+	}
+	return convertGoValue(value, goType, scalar.GoType)
+}
+
+// fromGraphqlValue expression converting value of the go type of the graphql scalar to the go type
+func fromGraphqlValue(value, goType string, scalar graphqlScalar) (string, bool) {
+	if scalar.GoType == "graphql.Time" {
+		return value + ".Time", true
+	}
+	if scalar.Base != "" {
+		return convertGoValue(fmt.Sprintf("%s(%s)", scalar.Base, value), scalar.Base, goType)
+	}
+	return convertGoValue(value, scalar.GoType, goType)
+}
+
+// GraphqlFieldName name of the field in the graphql schema
+func (f *FieldInfo) GraphqlFieldName() string {
+	return graphqlName(f.GoFieldName)
+}
+
+// GraphqlSupported reports if the field has a graphql scalar, fields without one are left out of the schema
+func (f *FieldInfo) GraphqlSupported() bool {
+	_, ok := graphqlScalarOf(f)
+	return ok
+}
+
+// GraphqlType type of the field in the graphql schema, nullable columns are nullable
+func (f *FieldInfo) GraphqlType() string {
+	scalar, ok := graphqlScalarOf(f)
+	if !ok {
+		return ""
+	}
+
+	if isNullableGoType(f.GoFieldType) {
+		return scalar.Name
+	}
+	return scalar.Name + "!"
+}
+
+// GraphqlInputType type of the field in the input object of the table, input fields are optional
+func (f *FieldInfo) GraphqlInputType() string {
+	scalar, _ := graphqlScalarOf(f)
+	return scalar.Name
+}
+
+// GraphqlArgType type of the field when used as a non null argument such as a primary key
+func (f *FieldInfo) GraphqlArgType() string {
+	scalar, _ := graphqlScalarOf(f)
+	return scalar.Name + "!"
+}
+
+// GraphqlGoType go type returned by the resolver of the field
+func (f *FieldInfo) GraphqlGoType() string {
+	scalar, _ := graphqlScalarOf(f)
+	if isNullableGoType(f.GoFieldType) {
+		return "*" + scalar.GoType
+	}
+	return scalar.GoType
+}
+
+// GraphqlArgGoType go type an argument of the field is bound to, see GraphqlArgType
+func (f *FieldInfo) GraphqlArgGoType() string {
+	scalar, _ := graphqlScalarOf(f)
+	return scalar.GoType
+}
+
+// GraphqlInputGoType go type an input field of the field is bound to, see GraphqlInputType
+func (f *FieldInfo) GraphqlInputGoType() string {
+	scalar, _ := graphqlScalarOf(f)
+	return "*" + scalar.GoType
+}
+
+// GraphqlArg expression converting the argument read from src to the go type of the field, used to pass primary keys to the dao
+func (f *FieldInfo) GraphqlArg(src string) string {
+	scalar, ok := graphqlScalarOf(f)
+	if !ok {
+		return fmt.Sprintf("*new(%s) /* unsupported graphql type: %s */", f.GoFieldType, f.GoFieldType)
+	}
+
+	value, ok := fromGraphqlValue(fmt.Sprintf("%s.%s", src, f.GoFieldName), f.GoFieldType, scalar)
+	if !ok {
+		return fmt.Sprintf("*new(%s) /* unsupported conversion of %s to %s */", f.GoFieldType, scalar.GoType, f.GoFieldType)
+	}
+	return value
+}
+
+// GraphqlResolverCode statements of the resolver returning the field of the model src, NULL values are returned as nil
+func (f *FieldInfo) GraphqlResolverCode(src string) string {
+	scalar, ok := graphqlScalarOf(f)
+	if !ok {
+		return fmt.Sprintf("\n\tpanic(\"unsupported graphql type: %s\")", f.GoFieldType)
+	}
+
+	value, isNull := nullableValue(fmt.Sprintf("%s.%s", src, f.GoFieldName), f.GoFieldType)
+	value, ok = toGraphqlValue(value, goBaseType(f.GoFieldType), scalar)
+	if !ok {
+		return fmt.Sprintf("\n\tpanic(\"unsupported conversion of %s to %s\")", f.GoFieldType, scalar.GoType)
+	}
+
+	if isNull == "" {
+		return fmt.Sprintf("\n\treturn %s", value)
+	}
+	return fmt.Sprintf("\n\tif %s {\n\t\treturn nil\n\t}\n\n\tvalue := %s\n\treturn &value", isNull, value)
+}
+
+// GraphqlInputCode statement copying the input field of src to the model dst when the field is set, null input fields are
+// stored as NULL
+func (f *FieldInfo) GraphqlInputCode(src, dst string) string {
+	scalar, ok := graphqlScalarOf(f)
+	if !ok {
+		return fmt.Sprintf("\n\t// %s not converted, unsupported graphql type: %s", f.GoFieldName, f.GoFieldType)
+	}
+
+	field := fmt.Sprintf("%s.%s", src, f.GoFieldName)
+	value := "*" + field
+	if scalar.GoType == "graphql.Time" {
+		value = field
+	}
+
+	value, ok = fromGraphqlValue(value, goBaseType(f.GoFieldType), scalar)
+	if !ok {
+		return fmt.Sprintf("\n\t// %s not converted, unsupported conversion of %s to %s", f.GoFieldName, scalar.GoType, f.GoFieldType)
+	}
+
+	target := fmt.Sprintf("%s.%s", dst, f.GoFieldName)
+	if n, ok := nullableTypes[f.GoFieldType]; ok {
+		return fmt.Sprintf("\n\tif %s != nil {\n\t\t%s = %s\n\t}", field, target, fmt.Sprintf(n.Ctor, value, "true"))
+	}
+
+	if strings.HasPrefix(f.GoFieldType, "*") {
+		return fmt.Sprintf("\n\tif %s != nil {\n\t\tvalue := %s\n\t\t%s = &value\n\t}", field, value, target)
+	}
+	return fmt.Sprintf("\n\tif %s != nil {\n\t\t%s = %s\n\t}", field, target, value)
+}
+
+// GraphqlFieldName name of the field of the referenced record in the graphql schema
+func (fk *ForeignKeyInfo) GraphqlFieldName() string {
+	return graphqlName(fk.Name)
+}
+
+// GraphqlResolverCode statements of the resolver loading the record referenced by the model src with the dao package, the record is
+// nil when the key is NULL or the referenced record does not exist
+func (fk *ForeignKeyInfo) GraphqlResolverCode(src, daoPackageName string) string {
+	value, isNull := nullableValue(fmt.Sprintf("%s.%s", src, fk.Field.GoFieldName), fk.Field.GoFieldType)
+	value, ok := convertGoValue(value, goBaseType(fk.Field.GoFieldType), fk.Column.GoFieldType)
+	if !ok {
+		return fmt.Sprintf("\n\treturn nil, fmt.Errorf(\"unsupported conversion of %s to %s\")", fk.Field.GoFieldType, fk.Column.GoFieldType)
+	}
+
+	var b strings.Builder
+	if isNull != "" {
+		fmt.Fprintf(&b, "\n\tif %s {\n\t\treturn nil, nil\n\t}\n", isNull)
+	}
+	fmt.Fprintf(&b, "\n\trecord, err := %s.Get%s(ctx, %s)", daoPackageName, fk.Table.StructName, value)
+	fmt.Fprintf(&b, "\n\tif err == %s.ErrNotFound {\n\t\treturn nil, nil\n\t}", daoPackageName)
+	fmt.Fprintf(&b, "\n\tif err != nil {\n\t\treturn nil, graphqlError(err)\n\t}\n")
+	fmt.Fprintf(&b, "\n\treturn &%sResolver{record: record}, nil", fk.Table.StructName)
+	return b.String()
+}
+
+// GraphqlGetName name of the query returning a record of the table
+func (m *ModelInfo) GraphqlGetName() string {
+	return graphqlName(m.StructName)
+}
+
+// GraphqlListName name of the query returning a page of records of the table, all is prefixed when the plural is the singular
+func (m *ModelInfo) GraphqlListName() string {
+	plural := inflection.Plural(m.StructName)
+	if plural == m.StructName {
+		return "all" + plural
+	}
+	return graphqlName(plural)
+}
+
+// GraphqlListMethodName go name of the resolver of the list query
+func (m *ModelInfo) GraphqlListMethodName() string {
+	name := m.GraphqlListName()
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// GraphqlPrimaryKeyFields primary key fields when they all have a graphql scalar, get, update and delete are only generated for
+// these tables
+func (m *ModelInfo) GraphqlPrimaryKeyFields() []*FieldInfo {
+	fields := m.PrimaryKeyFields()
+	for _, f := range fields {
+		if !f.GraphqlSupported() {
+			return nil
+		}
+	}
+	return fields
+}
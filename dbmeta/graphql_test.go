@@ -0,0 +1,112 @@
+package dbmeta
+
+import (
+	"testing"
+)
+
+func Test_graphqlName(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"ID", "id"},
+		{"AlbumID", "albumID"},
+		{"URLPath", "urlPath"},
+		{"FirstName", "firstName"},
+		{"A", "a"},
+	}
+
+	for _, tt := range tests {
+		name := graphqlName(tt.name)
+		if name != tt.expected {
+			t.Errorf("%s: expect: %s, but got %s", tt.name, tt.expected, name)
+		}
+	}
+}
+
+func Test_graphqlConversionCode(t *testing.T) {
+	tests := []struct {
+		field    *FieldInfo
+		sdl      string
+		resolver string
+		input    string
+	}{
+		{&FieldInfo{GoFieldName: "ID", GoFieldType: "int"},
+			"Int64!",
+			"\n\treturn Int64(int64(r.ID))",
+			"\n\tif in.ID != nil {\n\t\tm.ID = int(int64(*in.ID))\n\t}"},
+		{&FieldInfo{GoFieldName: "Quantity", GoFieldType: "int16"},
+			"Int!",
+			"\n\treturn int32(r.Quantity)",
+			"\n\tif in.Quantity != nil {\n\t\tm.Quantity = int16(*in.Quantity)\n\t}"},
+		{&FieldInfo{GoFieldName: "Size", GoFieldType: "uint32"},
+			"Int64!",
+			"\n\treturn UInt32(r.Size)",
+			"\n\tif in.Size != nil {\n\t\tm.Size = uint32(*in.Size)\n\t}"},
+		{&FieldInfo{GoFieldName: "ReportsTo", GoFieldType: "sql.NullInt64"},
+			"Int64",
+			"\n\tif !r.ReportsTo.Valid {\n\t\treturn nil\n\t}\n\n\tvalue := Int64(r.ReportsTo.Int64)\n\treturn &value",
+			"\n\tif in.ReportsTo != nil {\n\t\tm.ReportsTo = sql.NullInt64{Int64: int64(*in.ReportsTo), Valid: true}\n\t}"},
+		{&FieldInfo{GoFieldName: "Company", GoFieldType: "sql.NullString"},
+			"String",
+			"\n\tif !r.Company.Valid {\n\t\treturn nil\n\t}\n\n\tvalue := r.Company.String\n\treturn &value",
+			"\n\tif in.Company != nil {\n\t\tm.Company = sql.NullString{String: *in.Company, Valid: true}\n\t}"},
+		{&FieldInfo{GoFieldName: "CreatedAt", GoFieldType: "null.Time"},
+			"Time",
+			"\n\tif !r.CreatedAt.Valid {\n\t\treturn nil\n\t}\n\n\tvalue := graphql.Time{Time: r.CreatedAt.Time}\n\treturn &value",
+			"\n\tif in.CreatedAt != nil {\n\t\tm.CreatedAt = null.NewTime(in.CreatedAt.Time, true)\n\t}"},
+		{&FieldInfo{GoFieldName: "DeletedAt", GoFieldType: "*time.Time"},
+			"Time",
+			"\n\tif r.DeletedAt == nil {\n\t\treturn nil\n\t}\n\n\tvalue := graphql.Time{Time: *r.DeletedAt}\n\treturn &value",
+			"\n\tif in.DeletedAt != nil {\n\t\tvalue := in.DeletedAt.Time\n\t\tm.DeletedAt = &value\n\t}"},
+		{&FieldInfo{GoFieldName: "Data", GoFieldType: "interface{}"},
+			"",
+			"\n\tpanic(\"unsupported graphql type: interface{}\")",
+			"\n\t// Data not converted, unsupported graphql type: interface{}"},
+	}
+
+	for _, tt := range tests {
+		if sdl := tt.field.GraphqlType(); sdl != tt.sdl {
+			t.Errorf("%s type: expect: %q, but got %q", tt.field.GoFieldType, tt.sdl, sdl)
+		}
+		if code := tt.field.GraphqlResolverCode("r"); code != tt.resolver {
+			t.Errorf("%s resolver: expect: %q, but got %q", tt.field.GoFieldType, tt.resolver, code)
+		}
+		if code := tt.field.GraphqlInputCode("in", "m"); code != tt.input {
+			t.Errorf("%s input: expect: %q, but got %q", tt.field.GoFieldType, tt.input, code)
+		}
+	}
+}
+
+func Test_linkForeignKeys(t *testing.T) {
+	employeeID := &FieldInfo{GoFieldName: "EmployeeID", GoFieldType: "int", PrimaryKeyArgName: "argEmployeeID",
+		ColumnMeta: &testColumn{name: "EmployeeId", primaryKey: true}}
+	reportsTo := &FieldInfo{GoFieldName: "ReportsTo", GoFieldType: "sql.NullInt64",
+		ColumnMeta: &testColumn{name: "ReportsTo", foreignKey: &ForeignKey{Table: "employees", Column: "EmployeeId"}}}
+	employees := &ModelInfo{StructName: "Employee", TableName: "employees", CodeFields: []*FieldInfo{employeeID, reportsTo}}
+
+	trackID := &FieldInfo{GoFieldName: "TrackID", GoFieldType: "int", PrimaryKeyArgName: "argTrackID",
+		ColumnMeta: &testColumn{name: "TrackId", primaryKey: true}}
+	album := &FieldInfo{GoFieldName: "Album", GoFieldType: "string", ColumnMeta: &testColumn{name: "Album"}}
+	albumID := &FieldInfo{GoFieldName: "AlbumID", GoFieldType: "int",
+		ColumnMeta: &testColumn{name: "AlbumId", foreignKey: &ForeignKey{Table: "albums", Column: "AlbumId"}}}
+	supportRepID := &FieldInfo{GoFieldName: "SupportRepID", GoFieldType: "int",
+		ColumnMeta: &testColumn{name: "SupportRepId", foreignKey: &ForeignKey{Table: "employees", Column: "EmployeeId"}}}
+	tracks := &ModelInfo{StructName: "Track", TableName: "tracks", CodeFields: []*FieldInfo{trackID, album, albumID, supportRepID}}
+
+	linkForeignKeys(map[string]*ModelInfo{"employees": employees, "tracks": tracks})
+
+	if len(employees.ForeignKeys) != 1 || employees.ForeignKeys[0].Name != "Employee" || employees.ForeignKeys[0].Column != employeeID {
+		t.Errorf("employees: expect a single Employee foreign key, but got %+v", employees.ForeignKeys)
+	}
+
+	// albums is not generated, the key is not linked
+	if len(tracks.ForeignKeys) != 1 || tracks.ForeignKeys[0].Name != "SupportRep" || tracks.ForeignKeys[0].Field != supportRepID {
+		t.Errorf("tracks: expect a single SupportRep foreign key, but got %+v", tracks.ForeignKeys)
+	}
+
+	name := foreignKeyName(tracks, albumID, employees)
+	if name != "AlbumRecord" {
+		t.Errorf("expect AlbumRecord for a name colliding with a field, but got %s", name)
+	}
+}
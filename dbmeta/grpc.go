@@ -0,0 +1,240 @@
+package dbmeta
+
+import (
+	"fmt"
+	"strings"
+)
+
+// nullableType describes a sql or guregu nullable go type, Value is the field holding the value and Ctor the printf format
+// building a value from the value and valid expressions
+type nullableType struct {
+	Value string
+	Type  string
+	Ctor  string
+}
+
+var nullableTypes = map[string]nullableType{
+	"sql.NullString":  {"String", "string", "sql.NullString{String: %s, Valid: %s}"},
+	"sql.NullInt64":   {"Int64", "int64", "sql.NullInt64{Int64: %s, Valid: %s}"},
+	"sql.NullInt32":   {"Int32", "int32", "sql.NullInt32{Int32: %s, Valid: %s}"},
+	"sql.NullFloat64": {"Float64", "float64", "sql.NullFloat64{Float64: %s, Valid: %s}"},
+	"sql.NullBool":    {"Bool", "bool", "sql.NullBool{Bool: %s, Valid: %s}"},
+	"sql.NullTime":    {"Time", "time.Time", "sql.NullTime{Time: %s, Valid: %s}"},
+	"null.String":     {"String", "string", "null.NewString(%s, %s)"},
+	"null.Int":        {"Int64", "int64", "null.NewInt(%s, %s)"},
+	"null.Float":      {"Float64", "float64", "null.NewFloat(%s, %s)"},
+	"null.Bool":       {"Bool", "bool", "null.NewBool(%s, %s)"},
+	"null.Time":       {"Time", "time.Time", "null.NewTime(%s, %s)"},
+}
+
+// ProtobufGoFieldName name of the go field protoc-gen-go generates for the protobuf field
+func (f *FieldInfo) ProtobufGoFieldName() string {
+	return protobufGoName(f.ProtobufFieldName)
+}
+
+// protobufGoName port of the protoc-gen-go camel casing of protobuf names
+func protobufGoName(s string) string {
+	var b []byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '.' && i+1 < len(s) && isASCIILower(s[i+1]):
+		case c == '.':
+			b = append(b, '_')
+		case c == '_' && (i == 0 || s[i-1] == '.'):
+			b = append(b, 'X')
+		case c == '_' && i+1 < len(s) && isASCIILower(s[i+1]):
+		case c >= '0' && c <= '9':
+			b = append(b, c)
+		default:
+			if isASCIILower(c) {
+				c -= 'a' - 'A'
+			}
+			b = append(b, c)
+
+			for ; i+1 < len(s) && isASCIILower(s[i+1]); i++ {
+				b = append(b, s[i+1])
+			}
+		}
+	}
+	return string(b)
+}
+
+func isASCIILower(c byte) bool {
+	return 'a' <= c && c <= 'z'
+}
+
+func isNumericGoType(goType string) bool {
+	switch goType {
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "float32", "float64":
+		return true
+	}
+	return false
+}
+
+// goBaseType the type of the value held by a field, pointer and nullable types hold a plain value
+func goBaseType(goType string) string {
+	if n, ok := nullableTypes[goType]; ok {
+		return n.Type
+	}
+	return strings.TrimPrefix(goType, "*")
+}
+
+// convertGoValue expression converting value from one go type to another, returns false when there is no conversion.
+// Times are converted to seconds since the epoch with the unixTime and timeFromUnix helpers of the grpc package.
+func convertGoValue(value, from, to string) (string, bool) {
+	switch {
+	case from == to:
+		return value, true
+	case from == "time.Time" && isNumericGoType(to):
+		return fmt.Sprintf("%s(unixTime(%s))", to, value), true
+	case isNumericGoType(from) && to == "time.Time":
+		return fmt.Sprintf("timeFromUnix(int64(%s))", value), true
+	case isNumericGoType(from) && isNumericGoType(to):
+		return fmt.Sprintf("%s(%s)", to, value), true
+	case isNumericGoType(from) && to == "bool":
+		return fmt.Sprintf("%s != 0", value), true
+	case from == "[]byte" && to == "string":
+		return fmt.Sprintf("string(%s)", value), true
+	case from == "string" && to == "[]byte":
+		return fmt.Sprintf("[]byte(%s)", value), true
+	}
+	return "", false
+}
+
+// protobufValue describes how the go value of a protobuf field is read and written, wrappers and timestamps are messages that are
+// nil when the column is NULL, proto3 scalars cannot be null so their zero value is treated as NULL
+type protobufValue struct {
+	goType   string
+	pbType   string
+	wrapper  bool
+	timeType bool
+}
+
+func newProtobufValue(pbType string) (*protobufValue, bool) {
+	valueType, ok := protobufValueType(pbType)
+	if !ok {
+		return nil, false
+	}
+
+	return &protobufValue{
+		goType:   valueType,
+		pbType:   pbType,
+		wrapper:  strings.HasPrefix(protobufGoTypes[pbType], "*wrapperspb."),
+		timeType: pbType == "google.protobuf.Timestamp",
+	}, true
+}
+
+// wrap expression building the protobuf go value from value
+func (v *protobufValue) wrap(value string) string {
+	switch {
+	case v.wrapper:
+		name := strings.TrimSuffix(strings.TrimPrefix(v.pbType, "google.protobuf."), "Value")
+		return fmt.Sprintf("wrapperspb.%s(%s)", name, value)
+	case v.timeType:
+		return fmt.Sprintf("timestampFromTime(%s)", value)
+	}
+	return value
+}
+
+// unwrap expression reading the value of the protobuf go value field
+func (v *protobufValue) unwrap(field string) string {
+	switch {
+	case v.wrapper:
+		return fmt.Sprintf("%s.GetValue()", field)
+	case v.timeType:
+		return fmt.Sprintf("timeFromTimestamp(%s)", field)
+	}
+	return field
+}
+
+// isSet expression reporting if the protobuf field holds a value, bools are always set
+func (v *protobufValue) isSet(field string) string {
+	if v.wrapper || v.timeType {
+		return fmt.Sprintf("%s != nil", field)
+	}
+
+	switch v.goType {
+	case "bool":
+		return "true"
+	case "string":
+		return fmt.Sprintf(`%s != ""`, field)
+	case "[]byte":
+		return fmt.Sprintf("len(%s) > 0", field)
+	}
+	return fmt.Sprintf("%s != 0", field)
+}
+
+// ProtobufArg expression converting the protobuf field read from src to the go type of the field, used to pass primary keys to the dao
+func (f *FieldInfo) ProtobufArg(src string) string {
+	pbValue, ok := newProtobufValue(f.ProtobufType)
+	if !ok {
+		return fmt.Sprintf("*new(%s) /* unsupported protobuf type: %s */", f.GoFieldType, f.ProtobufType)
+	}
+
+	value, ok := convertGoValue(pbValue.unwrap(fmt.Sprintf("%s.%s", src, f.ProtobufGoFieldName())), pbValue.goType, f.GoFieldType)
+	if !ok {
+		return fmt.Sprintf("*new(%s) /* unsupported conversion of %s to %s */", f.GoFieldType, f.ProtobufType, f.GoFieldType)
+	}
+	return value
+}
+
+// ToProtobufCode statement copying the field of the model src to the protobuf message dst, NULL values are left unset
+func (f *FieldInfo) ToProtobufCode(src, dst string) string {
+	pbValue, ok := newProtobufValue(f.ProtobufType)
+	if !ok {
+		return fmt.Sprintf("\n\t// %s not converted, unsupported protobuf type: %s", f.GoFieldName, f.ProtobufType)
+	}
+
+	field := fmt.Sprintf("%s.%s", src, f.GoFieldName)
+	baseType := goBaseType(f.GoFieldType)
+
+	value := field
+	valid := ""
+	if n, ok := nullableTypes[f.GoFieldType]; ok {
+		value = fmt.Sprintf("%s.%s", field, n.Value)
+		valid = field + ".Valid"
+	} else if strings.HasPrefix(f.GoFieldType, "*") {
+		value = "*" + field
+		valid = field + " != nil"
+	}
+
+	value, ok = convertGoValue(value, baseType, pbValue.goType)
+	if !ok {
+		return fmt.Sprintf("\n\t// %s not converted, unsupported conversion of %s to %s", f.GoFieldName, f.GoFieldType, f.ProtobufType)
+	}
+
+	assign := fmt.Sprintf("%s.%s = %s", dst, f.ProtobufGoFieldName(), pbValue.wrap(value))
+	if valid == "" {
+		return fmt.Sprintf("\n\t%s", assign)
+	}
+	return fmt.Sprintf("\n\tif %s {\n\t\t%s\n\t}", valid, assign)
+}
+
+// FromProtobufCode statement copying the field of the protobuf message src to the model dst, unset fields are stored as NULL
+func (f *FieldInfo) FromProtobufCode(src, dst string) string {
+	pbValue, ok := newProtobufValue(f.ProtobufType)
+	if !ok {
+		return fmt.Sprintf("\n\t// %s not converted, unsupported protobuf type: %s", f.GoFieldName, f.ProtobufType)
+	}
+
+	field := fmt.Sprintf("%s.%s", src, f.ProtobufGoFieldName())
+	baseType := goBaseType(f.GoFieldType)
+
+	value, ok := convertGoValue(pbValue.unwrap(field), pbValue.goType, baseType)
+	if !ok {
+		return fmt.Sprintf("\n\t// %s not converted, unsupported conversion of %s to %s", f.GoFieldName, f.ProtobufType, f.GoFieldType)
+	}
+
+	target := fmt.Sprintf("%s.%s", dst, f.GoFieldName)
+	valid := pbValue.isSet(field)
+
+	if n, ok := nullableTypes[f.GoFieldType]; ok {
+		return fmt.Sprintf("\n\t%s = %s", target, fmt.Sprintf(n.Ctor, value, valid))
+	}
+
+	if strings.HasPrefix(f.GoFieldType, "*") {
+		return fmt.Sprintf("\n\tif %s {\n\t\tvalue := %s\n\t\t%s = &value\n\t}", valid, value, target)
+	}
+	return fmt.Sprintf("\n\t%s = %s", target, value)
+}
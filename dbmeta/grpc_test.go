@@ -0,0 +1,67 @@
+package dbmeta
+
+import (
+	"testing"
+)
+
+func Test_protobufGoName(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"first_name", "FirstName"},
+		{"customer_id", "CustomerId"},
+		{"FirstName", "FirstName"},
+		{"address_2", "Address_2"},
+		{"_id", "XId"},
+	}
+
+	for _, tt := range tests {
+		name := protobufGoName(tt.name)
+		if name != tt.expected {
+			t.Errorf("%s: expect: %s, but got %s", tt.name, tt.expected, name)
+		}
+	}
+}
+
+func Test_protobufConversionCode(t *testing.T) {
+	tests := []struct {
+		field *FieldInfo
+		to    string
+		from  string
+	}{
+		{&FieldInfo{GoFieldName: "ID", GoFieldType: "int", ProtobufFieldName: "id", ProtobufType: "int32"},
+			"\n\tm.Id = int32(r.ID)",
+			"\n\tr.ID = int(m.Id)"},
+		{&FieldInfo{GoFieldName: "Company", GoFieldType: "sql.NullString", ProtobufFieldName: "company", ProtobufType: "string"},
+			"\n\tif r.Company.Valid {\n\t\tm.Company = r.Company.String\n\t}",
+			"\n\tr.Company = sql.NullString{String: m.Company, Valid: m.Company != \"\"}"},
+		{&FieldInfo{GoFieldName: "CreatedAt", GoFieldType: "null.Time", ProtobufFieldName: "created_at", ProtobufType: "uint64"},
+			"\n\tif r.CreatedAt.Valid {\n\t\tm.CreatedAt = uint64(unixTime(r.CreatedAt.Time))\n\t}",
+			"\n\tr.CreatedAt = null.NewTime(timeFromUnix(int64(m.CreatedAt)), m.CreatedAt != 0)"},
+		{&FieldInfo{GoFieldName: "DeletedAt", GoFieldType: "*time.Time", ProtobufFieldName: "deleted_at", ProtobufType: "uint64"},
+			"\n\tif r.DeletedAt != nil {\n\t\tm.DeletedAt = uint64(unixTime(*r.DeletedAt))\n\t}",
+			"\n\tif m.DeletedAt != 0 {\n\t\tvalue := timeFromUnix(int64(m.DeletedAt))\n\t\tr.DeletedAt = &value\n\t}"},
+		{&FieldInfo{GoFieldName: "Company", GoFieldType: "null.String", ProtobufFieldName: "company", ProtobufType: "google.protobuf.StringValue"},
+			"\n\tif r.Company.Valid {\n\t\tm.Company = wrapperspb.String(r.Company.String)\n\t}",
+			"\n\tr.Company = null.NewString(m.Company.GetValue(), m.Company != nil)"},
+		{&FieldInfo{GoFieldName: "UpdatedAt", GoFieldType: "time.Time", ProtobufFieldName: "updated_at", ProtobufType: "google.protobuf.Timestamp"},
+			"\n\tm.UpdatedAt = timestampFromTime(r.UpdatedAt)",
+			"\n\tr.UpdatedAt = timeFromTimestamp(m.UpdatedAt)"},
+		{&FieldInfo{GoFieldName: "Flags", GoFieldType: "int", ProtobufFieldName: "flags", ProtobufType: "int8"},
+			"\n\t// Flags not converted, unsupported protobuf type: int8",
+			"\n\t// Flags not converted, unsupported protobuf type: int8"},
+	}
+
+	for _, tt := range tests {
+		code := tt.field.ToProtobufCode("r", "m")
+		if code != tt.to {
+			t.Errorf("%s to protobuf: expect: %q, but got %q", tt.field.GoFieldType, tt.to, code)
+		}
+
+		code = tt.field.FromProtobufCode("m", "r")
+		if code != tt.from {
+			t.Errorf("%s from protobuf: expect: %q, but got %q", tt.field.GoFieldType, tt.from, code)
+		}
+	}
+}
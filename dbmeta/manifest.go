@@ -0,0 +1,106 @@
+package dbmeta
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+)
+
+// ManifestFileName name of the manifest written to the output dir, gen check reads it to compare the generated files
+const ManifestFileName = "gen_manifest.json"
+
+// Manifest records how the code in the output dir was generated, the command line, the schema it was generated from and
+// the files written
+type Manifest struct {
+	CommandLine string          `json:"command_line"`
+	SQLConnStr  string          `json:"sql_conn_str"`
+	Schema      *SchemaSnapshot `json:"schema"`
+	Files       []string        `json:"files"`
+}
+
+// Save writes the manifest as indented json
+func (m *Manifest) Save(fileName string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to marshal manifest: %v", err)
+	}
+	return ioutil.WriteFile(fileName, append(data, '\n'), 0644)
+}
+
+// LoadManifest reads a manifest saved with Save
+func LoadManifest(fileName string) (*Manifest, error) {
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Manifest{}
+	err = json.Unmarshal(data, m)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse manifest %s: %v", fileName, err)
+	}
+	if m.Schema == nil {
+		m.Schema = &SchemaSnapshot{}
+	}
+	return m, nil
+}
+
+// Changes describes the tables and columns that differ between the snapshot and another snapshot of the schema
+func (s *SchemaSnapshot) Changes(to *SchemaSnapshot) []string {
+	var changes []string
+
+	for _, t := range to.Tables {
+		if s.Table(t.Name) == nil {
+			changes = append(changes, fmt.Sprintf("table %s added", t.Name))
+		}
+	}
+
+	for _, from := range s.Tables {
+		t := to.Table(from.Name)
+		if t == nil {
+			changes = append(changes, fmt.Sprintf("table %s dropped", from.Name))
+			continue
+		}
+
+		for _, c := range t.Columns {
+			if from.column(c.Name) == nil {
+				changes = append(changes, fmt.Sprintf("column %s.%s added: %s", t.Name, c.Name, columnSummary(c)))
+			}
+		}
+
+		for _, fromColumn := range from.Columns {
+			c := t.column(fromColumn.Name)
+			switch {
+			case c == nil:
+				changes = append(changes, fmt.Sprintf("column %s.%s dropped", t.Name, fromColumn.Name))
+			case !columnSchemaEqual(fromColumn, c):
+				changes = append(changes, fmt.Sprintf("column %s.%s changed from %s to %s", t.Name, c.Name, columnSummary(fromColumn), columnSummary(c)))
+			}
+		}
+
+		if strings.Join(from.PrimaryKey, ",") != strings.Join(t.PrimaryKey, ",") {
+			changes = append(changes, fmt.Sprintf("table %s primary key changed from (%s) to (%s)", t.Name,
+				strings.Join(from.PrimaryKey, ", "), strings.Join(t.PrimaryKey, ", ")))
+		}
+	}
+
+	sort.Strings(changes)
+	return changes
+}
+
+// columnSummary type, nullability, default and auto increment of a column
+func columnSummary(c *ColumnSchema) string {
+	summary := c.Type
+	if !c.Nullable {
+		summary += " NOT NULL"
+	}
+	if c.Default != "" {
+		summary += " DEFAULT " + c.Default
+	}
+	if c.AutoIncrement {
+		summary += " AUTO_INCREMENT"
+	}
+	return summary
+}
@@ -0,0 +1,27 @@
+package dbmeta
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_SchemaSnapshotChanges(t *testing.T) {
+	from, to := migrateTestSnapshots("mysql")
+	to.Tables[0].PrimaryKey = []string{"id", "name"}
+
+	expected := strings.Join([]string{
+		"column artists.country added: varchar(40) DEFAULT 'NZ'",
+		"column artists.name changed from varchar(120) to varchar(200) NOT NULL",
+		"table albums dropped",
+		"table artists primary key changed from (id) to (id, name)",
+	}, "\n")
+
+	changes := strings.Join(from.Changes(to), "\n")
+	if changes != expected {
+		t.Errorf("expect: %s, but got %s", expected, changes)
+	}
+
+	if changes := from.Changes(from); len(changes) != 0 {
+		t.Errorf("expect no changes, but got %v", changes)
+	}
+}
@@ -5,15 +5,18 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"hash/fnv"
 	"io/ioutil"
+	"math/rand"
 	"os"
+	"reflect"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/bxcodec/faker/v3"
 	"github.com/iancoleman/strcase"
 	"github.com/jinzhu/inflection"
-	"github.com/ompluscator/dynamic-struct"
 )
 
 type metaDataLoader func(db *sql.DB, sqlType, sqlDatabase, tableName string) (DbTableMeta, error)
@@ -32,6 +35,9 @@ func init() {
 // SQLMappings mappings for sql types to json, go etc
 type SQLMappings struct {
 	SQLMappings []*SQLMapping `json:"mappings"`
+
+	// FakeDataRules rules of the fake data of the samples and seed data, applied before the rules loaded earlier
+	FakeDataRules []*FakeDataRule `json:"fake_data_rules"`
 }
 
 // SQLMapping mapping
@@ -92,9 +98,18 @@ type columnMeta struct {
 	columnType      string
 	columnLen       int64
 	defaultVal      string
+	enumValues      []string
+	foreignKey      *ForeignKey
 	notes           string
 }
 
+// ForeignKey table and column referenced by a foreign key column, Name is the name of the constraint when the db names it
+type ForeignKey struct {
+	Name   string
+	Table  string
+	Column string
+}
+
 // ColumnType column type
 func (ci *columnMeta) ColumnType() string {
 	return ci.columnType
@@ -115,6 +130,16 @@ func (ci *columnMeta) DefaultValue() string {
 	return ci.defaultVal
 }
 
+// EnumValues allowed values of an enum column, nil when the column is not an enum
+func (ci *columnMeta) EnumValues() []string {
+	return ci.enumValues
+}
+
+// ForeignKey table and column referenced by the column, nil when the column is not part of a single column foreign key
+func (ci *columnMeta) ForeignKey() *ForeignKey {
+	return ci.foreignKey
+}
+
 // Name name of column
 func (ci *columnMeta) Name() string {
 	return ci.ct.Name()
@@ -186,6 +211,8 @@ type ColumnMeta interface {
 	Notes() string
 	ColumnLength() int64
 	DefaultValue() string
+	EnumValues() []string
+	ForeignKey() *ForeignKey
 }
 
 type dbTableMeta struct {
@@ -244,6 +271,38 @@ type ModelInfo struct {
 	DBMeta          DbTableMeta
 	Instance        interface{}
 	CodeFields      []*FieldInfo
+	VersionField    *FieldInfo
+	SoftDeleteField *FieldInfo
+	CreatedAtField  *FieldInfo
+	UpdatedAtField  *FieldInfo
+	// ProtobufReserved field numbers of dropped columns, comma separated
+	ProtobufReserved string
+	// ProtobufReservedNames quoted field names of dropped columns, comma separated
+	ProtobufReservedNames string
+	// ForeignKeys fields referencing the primary key of another generated table
+	ForeignKeys []*ForeignKeyInfo
+	// UniqueKeys unique indexes the dao reads records by, loaded when the record cache is generated
+	UniqueKeys []*UniqueKeyInfo
+}
+
+// ForeignKeyInfo a field of a table referencing the primary key field of another generated table
+type ForeignKeyInfo struct {
+	// Name go name of the referenced record, the field name without its id suffix
+	Name   string
+	Field  *FieldInfo
+	Table  *ModelInfo
+	Column *FieldInfo
+}
+
+// PrimaryKeyFields fields of the primary key columns
+func (m *ModelInfo) PrimaryKeyFields() []*FieldInfo {
+	var fields []*FieldInfo
+	for _, f := range m.CodeFields {
+		if f.PrimaryKeyArgName != "" {
+			fields = append(fields, f)
+		}
+	}
+	return fields
 }
 
 // Notes notes on table generation
@@ -283,6 +342,17 @@ type FieldInfo struct {
 	PrimaryKeyFieldParser string
 	PrimaryKeyArgName     string
 	SqlMapping            *SQLMapping
+	Validation            *FieldValidation
+}
+
+// FieldValidation constraints of a field derived from the column meta data
+type FieldValidation struct {
+	Required  bool
+	MaxLength int64
+	HasRange  bool
+	Min       int64
+	Max       int64
+	Enum      []string
 }
 
 // LoadMeta loads the DbTableMeta data from the db connection for the table
@@ -317,9 +387,34 @@ func (c *Config) GenerateFieldsTypes(dbMeta DbTableMeta) ([]*FieldInfo, error) {
 
 	var fields []*FieldInfo
 	field := ""
+	softDeleteCol := findNamedColumn(dbMeta.Columns(), c.SoftDeleteColumnNames, isSoftDeleteColumn)
+
+	// columns maintained by the db or the dao are not validated
+	maintainedCols := []ColumnMeta{
+		softDeleteCol,
+		findNamedColumn(dbMeta.Columns(), c.VersionColumnNames, isVersionColumn),
+		findNamedColumn(dbMeta.Columns(), c.CreatedAtColumnNames, isTimestampColumn),
+		findNamedColumn(dbMeta.Columns(), c.UpdatedAtColumnNames, isTimestampColumn),
+	}
+
+	// field numbers are locked so that they stay the same when columns are reordered or dropped
+	var protobufNumbers map[string]int
+	if c.ProtobufLock != nil {
+		var names []string
+		for _, col := range dbMeta.Columns() {
+			names = append(names, col.Name())
+		}
+		protobufNumbers = c.ProtobufLock.LockFields(dbMeta.TableName(), c.ProtobufNameFormat, names)
+	}
+
 	for i, col := range dbMeta.Columns() {
 		name := col.Name()
 
+		protobufPos := i + 1
+		if n, ok := protobufNumbers[name]; ok {
+			protobufPos = n
+		}
+
 		valueType, err := SQLTypeToGoType(strings.ToLower(col.DatabaseTypeName()), col.Nullable(), c.UseGureguTypes)
 		if err != nil { // unknown type
 			fmt.Printf("table: %s unable to generate struct field: %s type: %s error: %v\n", dbMeta.TableName(), name, col.DatabaseTypeName(), err)
@@ -327,8 +422,26 @@ func (c *Config) GenerateFieldsTypes(dbMeta DbTableMeta) ([]*FieldInfo, error) {
 		}
 
 		fieldName := FmtFieldName(stringifyFirstChar(name))
+
+		// soft delete columns must be able to hold NULL, gorm only applies its soft delete handling to a field named DeletedAt
+		if col == softDeleteCol {
+			valueType = "*time.Time"
+			if c.UseGureguTypes {
+				valueType = "null.Time"
+			}
+
+			if c.AddGormAnnotation {
+				fieldName = "DeletedAt"
+			}
+		}
+
 		fieldName = checkDupeFieldName(fields, fieldName)
 
+		var validation *FieldValidation
+		if !col.IsAutoIncrement() && !containsColumn(maintainedCols, col) {
+			validation = createFieldValidation(dbMeta.SQLType(), col, valueType)
+		}
+
 		var annotations []string
 		if c.AddGormAnnotation {
 			annotations = append(annotations, createGormAnnotation(col))
@@ -343,12 +456,19 @@ func (c *Config) GenerateFieldsTypes(dbMeta DbTableMeta) ([]*FieldInfo, error) {
 		}
 
 		if c.AddProtobufAnnotation {
-			annnotation, err := createProtobufAnnotation(c.ProtobufNameFormat, col)
+			annnotation, err := createProtobufAnnotation(c.ProtobufNameFormat, col, protobufPos)
 			if err == nil {
 				annotations = append(annotations, annnotation)
 			}
 		}
 
+		if c.AddValidateAnnotation {
+			annotation := createValidateAnnotation(validation, valueType)
+			if annotation != "" {
+				annotations = append(annotations, annotation)
+			}
+		}
+
 		if len(annotations) > 0 {
 			field = fmt.Sprintf("%s %s `%s`",
 				fieldName,
@@ -363,6 +483,7 @@ func (c *Config) GenerateFieldsTypes(dbMeta DbTableMeta) ([]*FieldInfo, error) {
 		sqlMapping, _ := SQLTypeToMapping(strings.ToLower(col.DatabaseTypeName()))
 		goType, _ := SQLTypeToGoType(strings.ToLower(col.DatabaseTypeName()), false, false)
 		protobufType, _ := SQLTypeToProtobufType(col.DatabaseTypeName())
+		protobufType = protobufFieldType(protobufType, col.Nullable() || col == softDeleteCol)
 		fakeData := createFakeData(goType, fieldName)
 
 		//if c.Verbose {
@@ -391,10 +512,11 @@ func (c *Config) GenerateFieldsTypes(dbMeta DbTableMeta) ([]*FieldInfo, error) {
 			JSONFieldName:         formatFieldName(c.JsonNameFormat, col),
 			ProtobufFieldName:     formatFieldName(c.ProtobufNameFormat, col),
 			ProtobufType:          protobufType,
-			ProtobufPos:           i + 1,
+			ProtobufPos:           protobufPos,
 			ColumnMeta:            col,
 			PrimaryKeyFieldParser: primaryKeyFieldParser,
 			SqlMapping:            sqlMapping,
+			Validation:            validation,
 		}
 
 		fields = append(fields, fi)
@@ -403,19 +525,23 @@ func (c *Config) GenerateFieldsTypes(dbMeta DbTableMeta) ([]*FieldInfo, error) {
 }
 
 func formatFieldName(nameFormat string, c ColumnMeta) string {
+	return formatName(nameFormat, c.Name())
+}
+
+func formatName(nameFormat, name string) string {
 
 	var jsonName string
 	switch nameFormat {
 	case "snake":
-		jsonName = strcase.ToSnake(c.Name())
+		jsonName = strcase.ToSnake(name)
 	case "camel":
-		jsonName = strcase.ToCamel(c.Name())
+		jsonName = strcase.ToCamel(name)
 	case "lower_camel":
-		jsonName = strcase.ToLowerCamel(c.Name())
+		jsonName = strcase.ToLowerCamel(name)
 	case "none":
-		jsonName = c.Name()
+		jsonName = name
 	default:
-		jsonName = c.Name()
+		jsonName = name
 	}
 	return jsonName
 }
@@ -430,7 +556,7 @@ func createDBAnnotation(c ColumnMeta) string {
 	return fmt.Sprintf("db:\"%s\"", c.Name())
 }
 
-func createProtobufAnnotation(nameFormat string, c ColumnMeta) (string, error) {
+func createProtobufAnnotation(nameFormat string, c ColumnMeta, pos int) (string, error) {
 	protoBufType, err := SQLTypeToProtobufType(c.DatabaseTypeName())
 	if err != nil {
 		return "", err
@@ -438,7 +564,7 @@ func createProtobufAnnotation(nameFormat string, c ColumnMeta) (string, error) {
 
 	if protoBufType != "" {
 		name := formatFieldName(nameFormat, c)
-		return fmt.Sprintf("protobuf:\"%s,%d,opt,name=%s\"", protoBufType, c.Index(), name), nil
+		return fmt.Sprintf("protobuf:\"%s,%d,opt,name=%s\"", protoBufType, pos, name), nil
 	}
 
 	return "", fmt.Errorf("unknown sql name: %s", c.Name())
@@ -519,6 +645,15 @@ func ProcessMappings(mappingJsonstring []byte, verbose bool) error {
 
 		sqlMappings[value.SQLType] = value
 	}
+
+	if verbose && len(mappings.FakeDataRules) > 0 {
+		fmt.Printf("Loaded %d fake data rules\n", len(mappings.FakeDataRules))
+	}
+	err = AddFakeDataRules(mappings.FakeDataRules)
+	if err != nil {
+		fmt.Printf("Error loading fake data rules error: %v\n", err)
+		return err
+	}
 	return nil
 }
 
@@ -620,6 +755,19 @@ func createFakeData(valueType string, name string) interface{} {
 
 }
 
+// sampleTime is the base of the fake time values, faker offsets them from time.Now()
+var sampleTime = time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// setSampleTimes replaces the time fields of the fake instance with times offset from sampleTime
+func setSampleTimes(instance interface{}) {
+	v := reflect.ValueOf(instance).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if _, ok := v.Field(i).Interface().(time.Time); ok {
+			v.Field(i).Set(reflect.ValueOf(sampleTime.Add(time.Duration(rand.Int63n(int64(100 * 365 * 24 * time.Hour))))))
+		}
+	}
+}
+
 func LoadTableInfo(db *sql.DB, dbTables []string, conf *Config) map[string]*ModelInfo {
 
 	tableInfos := make(map[string]*ModelInfo)
@@ -650,6 +798,12 @@ func LoadTableInfo(db *sql.DB, dbTables []string, conf *Config) map[string]*Mode
 			continue
 		}
 
+		if conf.GenerateCache {
+			if err = loadUniqueKeys(db, conf.SqlType, dbMeta, modelInfo); err != nil {
+				fmt.Printf("Error loading unique keys for %s error: %v\n", tableName, err)
+			}
+		}
+
 		modelInfo.Index = tableIdx
 		modelInfo.IndexPlus1 = tableIdx + 1
 		tableIdx++
@@ -657,9 +811,75 @@ func LoadTableInfo(db *sql.DB, dbTables []string, conf *Config) map[string]*Mode
 		tableInfos[tableName] = modelInfo
 	}
 
+	linkForeignKeys(tableInfos)
 	return tableInfos
 }
 
+// linkForeignKeys sets the ForeignKeys of each table, only keys referencing the single primary key of a generated table are linked
+func linkForeignKeys(tableInfos map[string]*ModelInfo) {
+	for _, modelInfo := range tableInfos {
+		modelInfo.ForeignKeys = nil
+
+		for _, f := range modelInfo.CodeFields {
+			fk := f.ColumnMeta.ForeignKey()
+			if fk == nil {
+				continue
+			}
+
+			table, ok := tableInfos[fk.Table]
+			if !ok {
+				continue
+			}
+
+			pkFields := table.PrimaryKeyFields()
+			if len(pkFields) != 1 || !strings.EqualFold(pkFields[0].ColumnMeta.Name(), fk.Column) {
+				continue
+			}
+
+			modelInfo.ForeignKeys = append(modelInfo.ForeignKeys, &ForeignKeyInfo{
+				Name:   foreignKeyName(modelInfo, f, table),
+				Field:  f,
+				Table:  table,
+				Column: pkFields[0],
+			})
+		}
+	}
+}
+
+// foreignKeyName name of the record referenced by f, AlbumID references an Album and ReportsTo an Employee. Names colliding with a
+// field or another foreign key are suffixed with Record.
+func foreignKeyName(modelInfo *ModelInfo, f *FieldInfo, table *ModelInfo) string {
+	name := f.GoFieldName
+	for _, suffix := range []string{"ID", "Id", "_id"} {
+		if len(name) > len(suffix) && strings.HasSuffix(name, suffix) {
+			name = strings.TrimSuffix(name, suffix)
+			break
+		}
+	}
+	if name == f.GoFieldName {
+		name = table.StructName
+	}
+
+	taken := func(name string) bool {
+		for _, field := range modelInfo.CodeFields {
+			if strings.EqualFold(field.GoFieldName, name) {
+				return true
+			}
+		}
+		for _, fk := range modelInfo.ForeignKeys {
+			if strings.EqualFold(fk.Name, name) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for taken(name) {
+		name = name + "Record"
+	}
+	return name
+}
+
 // GenerateModelInfo generates a struct for the given table.
 func GenerateModelInfo(dbMeta DbTableMeta,
 	tableName string,
@@ -682,15 +902,19 @@ func GenerateModelInfo(dbMeta DbTableMeta,
 		}
 	}
 
-	generator := dynamicstruct.NewStruct()
+	// the sample struct keeps the column order so the samples are the same each time the code is generated
+	var sampleFields []reflect.StructField
+	sampleFieldNames := make(map[string]bool)
 
 	noOfPrimaryKeys := 0
 	for i, c := range fields {
 		meta := dbMeta.Columns()[i]
 		jsonName := formatFieldName(conf.JsonNameFormat, meta)
 		tag := fmt.Sprintf(`json:"%s"`, jsonName)
-		fakeData := c.FakeData
-		generator = generator.AddField(c.GoFieldName, fakeData, tag)
+		if !sampleFieldNames[c.GoFieldName] {
+			sampleFieldNames[c.GoFieldName] = true
+			sampleFields = append(sampleFields, reflect.StructField{Name: c.GoFieldName, Type: reflect.TypeOf(c.FakeData), Tag: reflect.StructTag(tag)})
+		}
 		if meta.IsPrimaryKey() {
 			//c.PrimaryKeyArgName = RenameReservedName(strcase.ToLowerCamel(c.GoFieldName))
 			c.PrimaryKeyArgName = fmt.Sprintf("arg%s", strcase.ToCamel(c.GoFieldName))
@@ -698,12 +922,19 @@ func GenerateModelInfo(dbMeta DbTableMeta,
 		}
 	}
 
-	instance := generator.Build().New()
+	instance := reflect.New(reflect.StructOf(sampleFields)).Interface()
+
+	// seeded with the table name so the samples are the same each time the code is generated
+	hash := fnv.New64a()
+	hash.Write([]byte(tableName))
+	rand.Seed(int64(hash.Sum64()))
 
-	err = faker.FakeData(&instance)
+	err = faker.FakeData(instance)
 	if err != nil {
 		fmt.Println(err)
 	}
+	setSampleTimes(instance)
+	setSampleValues(instance, fields)
 	// fmt.Printf("%+v", instance)
 
 	var code []string
@@ -725,7 +956,110 @@ func GenerateModelInfo(dbMeta DbTableMeta,
 		CodeFields:      fields,
 		DBMeta:          dbMeta,
 		Instance:        instance,
+		VersionField:    findNamedField(fields, conf.VersionColumnNames, isVersionField),
+		SoftDeleteField: findNamedField(fields, conf.SoftDeleteColumnNames, isSoftDeleteField),
+		CreatedAtField:  findNamedField(fields, conf.CreatedAtColumnNames, isTimestampField),
+		UpdatedAtField:  findNamedField(fields, conf.UpdatedAtColumnNames, isTimestampField),
+	}
+
+	if msg := conf.ProtobufLock.Message(dbMeta.TableName()); msg != nil {
+		var reserved, reservedNames []string
+		for _, n := range msg.Reserved {
+			reserved = append(reserved, strconv.Itoa(n))
+		}
+		for _, name := range msg.ReservedNames {
+			reservedNames = append(reservedNames, strconv.Quote(name))
+		}
+		modelInfo.ProtobufReserved = strings.Join(reserved, ", ")
+		modelInfo.ProtobufReservedNames = strings.Join(reservedNames, ", ")
 	}
 
 	return modelInfo, nil
 }
+
+// findNamedField returns the first non primary key field with a column name in names (case insensitive) that is accepted
+func findNamedField(fields []*FieldInfo, names []string, accept func(f *FieldInfo) bool) *FieldInfo {
+	for _, name := range names {
+		for _, f := range fields {
+			if f.ColumnMeta.IsPrimaryKey() || !strings.EqualFold(f.ColumnMeta.Name(), strings.TrimSpace(name)) {
+				continue
+			}
+
+			if accept(f) {
+				return f
+			}
+		}
+	}
+	return nil
+}
+
+// findNamedColumn returns the first non primary key column with a name in names (case insensitive) that is accepted
+func findNamedColumn(cols []ColumnMeta, names []string, accept func(col ColumnMeta) bool) ColumnMeta {
+	for _, name := range names {
+		for _, col := range cols {
+			if col.IsPrimaryKey() || !strings.EqualFold(col.Name(), strings.TrimSpace(name)) {
+				continue
+			}
+
+			if accept(col) {
+				return col
+			}
+		}
+	}
+	return nil
+}
+
+// isSoftDeleteColumn soft delete columns must be a nullable timestamp
+func isSoftDeleteColumn(col ColumnMeta) bool {
+	goType, err := SQLTypeToGoType(strings.ToLower(col.DatabaseTypeName()), false, false)
+	return err == nil && col.Nullable() && goType == "time.Time"
+}
+
+// isSoftDeleteField see isSoftDeleteColumn
+func isSoftDeleteField(f *FieldInfo) bool {
+	return isSoftDeleteColumn(f.ColumnMeta)
+}
+
+// isTimestampColumn created at and updated at columns must be a timestamp
+func isTimestampColumn(col ColumnMeta) bool {
+	goType, err := SQLTypeToGoType(strings.ToLower(col.DatabaseTypeName()), false, false)
+	return err == nil && goType == "time.Time"
+}
+
+// isTimestampField see isTimestampColumn
+func isTimestampField(f *FieldInfo) bool {
+	return isTimestampColumn(f.ColumnMeta)
+}
+
+// isVersionColumn version columns used for optimistic locking must be a non nullable integer
+func isVersionColumn(col ColumnMeta) bool {
+	if col.Nullable() {
+		return false
+	}
+
+	goType, err := SQLTypeToGoType(strings.ToLower(col.DatabaseTypeName()), false, false)
+	return err == nil && isIntegerType(goType)
+}
+
+// isVersionField see isVersionColumn
+func isVersionField(f *FieldInfo) bool {
+	return !f.ColumnMeta.Nullable() && isIntegerType(f.GoFieldType)
+}
+
+func isIntegerType(goType string) bool {
+	switch goType {
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64":
+		return true
+	default:
+		return false
+	}
+}
+
+func containsColumn(cols []ColumnMeta, col ColumnMeta) bool {
+	for _, c := range cols {
+		if c == col {
+			return true
+		}
+	}
+	return false
+}
@@ -90,6 +90,12 @@ func LoadMsSQLMeta(db *sql.DB, sqlType, sqlDatabase, tableName string) (DbTableM
 		m.columns[i] = colMeta
 	}
 
+	foreignKeys, err := msSQLLoadForeignKeys(db, tableName)
+	if err != nil {
+		fmt.Printf("error calling msSQLLoadForeignKeys table: %s error: %v\n", tableName, err)
+	}
+	setForeignKeys(m, foreignKeys)
+
 	m.ddl = BuildDefaultTableDDL(tableName, m.columns)
 	m = updateDefaultPrimaryKey(m)
 	return m, nil
@@ -129,6 +135,17 @@ WHERE
 	return nil
 }
 
+func msSQLLoadForeignKeys(db *sql.DB, tableName string) (map[string]*ForeignKey, error) {
+	foreignKeySQL := fmt.Sprintf(`
+SELECT OBJECT_NAME(fkc.constraint_object_id),
+    COL_NAME(fkc.parent_object_id, fkc.parent_column_id),
+    OBJECT_NAME(fkc.referenced_object_id),
+    COL_NAME(fkc.referenced_object_id, fkc.referenced_column_id)
+FROM sys.foreign_key_columns fkc
+WHERE fkc.parent_object_id = object_id('dbo.%s')`, tableName)
+	return loadForeignKeys(db, foreignKeySQL)
+}
+
 func msSQLloadFromSysColumns(db *sql.DB, tableName string) (colInfo map[string]*msSQLColumnInfo, err error) {
 	colInfo = make(map[string]*msSQLColumnInfo)
 
@@ -172,3 +189,59 @@ type msSQLColumnInfo struct {
 /*
 https://www.mssqltips.com/sqlservertip/1512/finding-and-listing-all-columns-in-a-sql-server-database-with-default-values/
 */
+
+// msSQLLoadTableSchema sets the column types with their length, precision and scale and the indexes of the table, indexes of
+// unique constraints are loaded as constraints. Comments are read from the MS_Description extended properties
+func msSQLLoadTableSchema(db *sql.DB, dbMeta DbTableMeta, t *TableSchema) error {
+	typeSQL := fmt.Sprintf(`
+SELECT COLUMN_NAME, DATA_TYPE, CHARACTER_MAXIMUM_LENGTH, NUMERIC_PRECISION, NUMERIC_SCALE
+FROM INFORMATION_SCHEMA.COLUMNS
+WHERE TABLE_NAME = '%s'`, t.Name)
+	res, err := db.Query(typeSQL)
+	if err != nil {
+		return fmt.Errorf("unable to load column types: %v", err)
+	}
+	defer res.Close()
+
+	for res.Next() {
+		var columnName, dataType string
+		var maxLength, precision, scale sql.NullInt64
+		err = res.Scan(&columnName, &dataType, &maxLength, &precision, &scale)
+		if err != nil {
+			return fmt.Errorf("unable to load column types Scan: %v", err)
+		}
+
+		c := t.column(columnName)
+		if c == nil {
+			continue
+		}
+
+		switch {
+		case maxLength.Valid && maxLength.Int64 == -1:
+			c.Type = fmt.Sprintf("%s(max)", dataType)
+		case maxLength.Valid && !strings.Contains(dataType, "text"):
+			c.Type = fmt.Sprintf("%s(%d)", dataType, maxLength.Int64)
+		case (dataType == "decimal" || dataType == "numeric") && precision.Valid && scale.Valid:
+			c.Type = fmt.Sprintf("%s(%d,%d)", dataType, precision.Int64, scale.Int64)
+		default:
+			c.Type = dataType
+		}
+	}
+
+	indexSQL := fmt.Sprintf(`
+SELECT i.name, COL_NAME(ic.object_id, ic.column_id), CAST(i.is_unique AS int), CAST(i.is_unique_constraint AS int)
+FROM sys.indexes i
+JOIN sys.index_columns ic ON ic.object_id = i.object_id AND ic.index_id = i.index_id
+WHERE i.object_id = object_id('dbo.%s') AND i.is_primary_key = 0 AND i.type > 0 AND ic.is_included_column = 0
+ORDER BY i.name, ic.key_ordinal`, t.Name)
+	t.Indexes, err = loadIndexes(db, indexSQL)
+	if err != nil {
+		return err
+	}
+
+	commentSQL := fmt.Sprintf(`
+SELECT COL_NAME(ep.major_id, ep.minor_id), CAST(ep.value AS nvarchar(max))
+FROM sys.extended_properties ep
+WHERE ep.class = 1 AND ep.name = 'MS_Description' AND ep.major_id = object_id('dbo.%s')`, t.Name)
+	return loadComments(db, commentSQL, t)
+}
@@ -70,6 +70,7 @@ func LoadMysqlMeta(db *sql.DB, sqlType, sqlDatabase, tableName string) (DbTableM
 			defaultVal:      defaultVal,
 			columnType:      columnType,
 			columnLen:       columnLen,
+			enumValues:      mysqlParseEnumValues(colDDL),
 		}
 
 		dbType := strings.ToLower(colMeta.DatabaseTypeName())
@@ -85,10 +86,25 @@ func LoadMysqlMeta(db *sql.DB, sqlType, sqlDatabase, tableName string) (DbTableM
 		m.columns[i] = colMeta
 	}
 
+	foreignKeys, err := mysqlLoadForeignKeys(db, tableName)
+	if err != nil {
+		fmt.Printf("error calling mysqlLoadForeignKeys table: %s error: %v\n", tableName, err)
+	}
+	setForeignKeys(m, foreignKeys)
+
 	m = updateDefaultPrimaryKey(m)
 	return m, nil
 }
 
+func mysqlLoadForeignKeys(db *sql.DB, tableName string) (map[string]*ForeignKey, error) {
+	foreignKeySQL := fmt.Sprintf(`
+	SELECT CONSTRAINT_NAME, COLUMN_NAME, REFERENCED_TABLE_NAME, REFERENCED_COLUMN_NAME
+	FROM information_schema.KEY_COLUMN_USAGE
+	WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = '%s' AND REFERENCED_TABLE_NAME IS NOT NULL;
+`, tableName)
+	return loadForeignKeys(db, foreignKeySQL)
+}
+
 func mysqlLoadDDL(db *sql.DB, tableName string) (ddl string, err error) {
 	ddlSQL := fmt.Sprintf("SHOW CREATE TABLE %s;", tableName)
 	res, err := db.Query(ddlSQL)
@@ -134,6 +150,40 @@ func mysqlParseDDL(ddl string) (colsDDL map[string]string, primaryKey string) {
 	return
 }
 
+// mysqlParseEnumValues parses the allowed values from a column ddl such as enum('small','large') NOT NULL
+func mysqlParseEnumValues(colDDL string) []string {
+	colDDL = strings.TrimSpace(colDDL)
+	if !strings.HasPrefix(strings.ToLower(colDDL), "enum(") {
+		return nil
+	}
+
+	var values []string
+	var value strings.Builder
+	inValue := false
+	for i := len("enum("); i < len(colDDL); i++ {
+		ch := colDDL[i]
+		switch {
+		case inValue && ch == '\'' && i+1 < len(colDDL) && colDDL[i+1] == '\'':
+			value.WriteByte(ch)
+			i++
+		case inValue && ch == '\\' && i+1 < len(colDDL):
+			value.WriteByte(colDDL[i+1])
+			i++
+		case ch == '\'':
+			if inValue {
+				values = append(values, value.String())
+				value.Reset()
+			}
+			inValue = !inValue
+		case inValue:
+			value.WriteByte(ch)
+		case ch == ')':
+			return values
+		}
+	}
+	return values
+}
+
 /*
 https://dataedo.com/kb/query/mysql/list-table-default-constraints
 
@@ -150,3 +200,118 @@ order by table_schema,
          table_name,
          ordinal_position;
 */
+
+// mysqlLoadTableSchema sets the column types and defaults as declared in the table ddl and the indexes of the table, mysql unique
+// keys are plain unique indexes. The table and column comments are loaded as well
+func mysqlLoadTableSchema(db *sql.DB, dbMeta DbTableMeta, t *TableSchema) error {
+	if m, ok := dbMeta.(*dbTableMeta); ok {
+		for _, col := range m.columns {
+			c := t.column(col.Name())
+			if c == nil || col.colDDL == "" {
+				continue
+			}
+
+			c.Type = mysqlParseColumnType(col.colDDL)
+			c.Default = mysqlParseColumnDefault(col.colDDL)
+		}
+	}
+
+	indexSQL := fmt.Sprintf(`
+	SELECT INDEX_NAME, COLUMN_NAME, 1 - NON_UNIQUE, 0
+	FROM information_schema.STATISTICS
+	WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = '%s' AND INDEX_NAME <> 'PRIMARY'
+	ORDER BY INDEX_NAME, SEQ_IN_INDEX;
+`, t.Name)
+	var err error
+	t.Indexes, err = loadIndexes(db, indexSQL)
+	if err != nil {
+		return err
+	}
+
+	commentSQL := fmt.Sprintf(`
+	SELECT '', TABLE_COMMENT FROM information_schema.TABLES
+	WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = '%s' AND TABLE_COMMENT <> ''
+	UNION ALL
+	SELECT COLUMN_NAME, COLUMN_COMMENT FROM information_schema.COLUMNS
+	WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = '%s' AND COLUMN_COMMENT <> '';
+`, t.Name, t.Name)
+	return loadComments(db, commentSQL, t)
+}
+
+// mysqlParseColumnType type of a column definition from SHOW CREATE TABLE such as int(10) unsigned or enum('a','b')
+func mysqlParseColumnType(colDDL string) string {
+	colDDL = strings.TrimSpace(colDDL)
+	depth := 0
+	inValue := false
+	end := len(colDDL)
+
+loop:
+	for i := 0; i < len(colDDL); i++ {
+		switch ch := colDDL[i]; {
+		case ch == '\'':
+			inValue = !inValue
+		case inValue:
+		case ch == '(':
+			depth++
+		case ch == ')':
+			depth--
+		case ch == ' ' && depth == 0:
+			end = i
+			break loop
+		}
+	}
+
+	typ := colDDL[:end]
+	for _, word := range strings.Fields(colDDL[end:]) {
+		if word != "unsigned" && word != "zerofill" {
+			break
+		}
+		typ += " " + word
+	}
+	return typ
+}
+
+// mysqlParseColumnDefault default of a column definition from SHOW CREATE TABLE as a sql literal or expression, empty when the
+// column does not have a default or defaults to NULL
+func mysqlParseColumnDefault(colDDL string) string {
+	idx := strings.Index(colDDL, " DEFAULT ")
+	if idx < 0 {
+		return ""
+	}
+	rest := colDDL[idx+len(" DEFAULT "):]
+
+	switch {
+	case strings.HasPrefix(rest, "'"):
+		for i := 1; i < len(rest); i++ {
+			switch {
+			case rest[i] == '\\':
+				i++
+			case rest[i] == '\'' && i+1 < len(rest) && rest[i+1] == '\'':
+				i++
+			case rest[i] == '\'':
+				return rest[:i+1]
+			}
+		}
+		return rest
+	case strings.HasPrefix(rest, "("):
+		depth := 0
+		for i := 0; i < len(rest); i++ {
+			switch rest[i] {
+			case '(':
+				depth++
+			case ')':
+				depth--
+				if depth == 0 {
+					return rest[:i+1]
+				}
+			}
+		}
+		return rest
+	}
+
+	words := strings.Fields(rest)
+	if len(words) == 0 || strings.EqualFold(words[0], "NULL") {
+		return ""
+	}
+	return words[0]
+}
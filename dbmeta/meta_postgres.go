@@ -32,6 +32,11 @@ func LoadPostgresMeta(db *sql.DB, sqlType, sqlDatabase, tableName string) (DbTab
 		return nil, fmt.Errorf("unable to load primary key from postgres: %v", err)
 	}
 
+	enumValues, err := postgresLoadEnumValues(db, tableName)
+	if err != nil {
+		fmt.Printf("error calling postgresLoadEnumValues table: %s error: %v\n", tableName, err)
+	}
+
 	for i, v := range cols {
 		defaultVal := ""
 		nullable, ok := v.Nullable()
@@ -77,11 +82,18 @@ func LoadPostgresMeta(db *sql.DB, sqlType, sqlDatabase, tableName string) (DbTab
 			columnLen:       maxLen,
 			columnType:      definedType,
 			defaultVal:      defaultVal,
+			enumValues:      enumValues[v.Name()],
 		}
 
 		m.columns[i] = colMeta
 	}
 
+	foreignKeys, err := postgresLoadForeignKeys(db, tableName)
+	if err != nil {
+		fmt.Printf("error calling postgresLoadForeignKeys table: %s error: %v\n", tableName, err)
+	}
+	setForeignKeys(m, foreignKeys)
+
 	m.ddl = BuildDefaultTableDDL(tableName, m.columns)
 	m = updateDefaultPrimaryKey(m)
 
@@ -131,6 +143,48 @@ func postgresLoadPrimaryKey(db *sql.DB, tableName string, colInfo map[string]*Po
 	return nil
 }
 
+// postgresLoadEnumValues loads the labels of enum typed columns in sort order, keyed by column name
+func postgresLoadEnumValues(db *sql.DB, tableName string) (map[string][]string, error) {
+	enumSQL := fmt.Sprintf(`
+	SELECT c.column_name, e.enumlabel
+	FROM information_schema.columns AS c
+	JOIN pg_type AS t ON t.typname = c.udt_name
+	JOIN pg_enum AS e ON e.enumtypid = t.oid
+	WHERE c.table_name = '%s'
+	ORDER BY c.column_name, e.enumsortorder;
+`, tableName)
+	res, err := db.Query(enumSQL)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load enum values from postgres: %v", err)
+	}
+	defer res.Close()
+
+	enumValues := make(map[string][]string)
+	for res.Next() {
+		var columnName, label string
+		err = res.Scan(&columnName, &label)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load enum values from postgres Scan: %v", err)
+		}
+
+		enumValues[columnName] = append(enumValues[columnName], label)
+	}
+	return enumValues, nil
+}
+
+func postgresLoadForeignKeys(db *sql.DB, tableName string) (map[string]*ForeignKey, error) {
+	foreignKeySQL := fmt.Sprintf(`
+	SELECT t.constraint_name, k.column_name, c.table_name, c.column_name
+	FROM information_schema.table_constraints AS t
+	JOIN information_schema.key_column_usage AS k
+	ON k.constraint_name = t.constraint_name AND k.table_schema = t.table_schema
+	JOIN information_schema.constraint_column_usage AS c
+	ON c.constraint_name = t.constraint_name AND c.table_schema = t.table_schema
+	WHERE t.table_name = '%s' AND t.constraint_type = 'FOREIGN KEY';
+`, tableName)
+	return loadForeignKeys(db, foreignKeySQL)
+}
+
 /*
 https://dataedo.com/kb/query/postgresql/list-table-default-constraints
 
@@ -143,3 +197,56 @@ where col.column_default is not null
       and col.table_schema not in('information_schema', 'pg_catalog')
 order by col.column_name;
 */
+
+// postgresLoadTableSchema sets the column types as formatted by postgres and the indexes of the table, indexes backing a unique
+// constraint are loaded as constraints. The table and column comments are loaded as well
+func postgresLoadTableSchema(db *sql.DB, dbMeta DbTableMeta, t *TableSchema) error {
+	typeSQL := fmt.Sprintf(`
+	SELECT a.attname, format_type(a.atttypid, a.atttypmod)
+	FROM pg_attribute AS a
+	JOIN pg_class AS t ON t.oid = a.attrelid
+	WHERE t.relname = '%s' AND t.relkind = 'r' AND a.attnum > 0 AND NOT a.attisdropped;
+`, t.Name)
+	res, err := db.Query(typeSQL)
+	if err != nil {
+		return fmt.Errorf("unable to load column types: %v", err)
+	}
+	defer res.Close()
+
+	for res.Next() {
+		var columnName, columnType string
+		err = res.Scan(&columnName, &columnType)
+		if err != nil {
+			return fmt.Errorf("unable to load column types Scan: %v", err)
+		}
+
+		if c := t.column(columnName); c != nil {
+			c.Type = columnType
+		}
+	}
+
+	indexSQL := fmt.Sprintf(`
+	SELECT i.relname, a.attname, ix.indisunique::int, (c.oid IS NOT NULL)::int
+	FROM pg_index AS ix
+	JOIN pg_class AS t ON t.oid = ix.indrelid
+	JOIN pg_class AS i ON i.oid = ix.indexrelid
+	JOIN LATERAL unnest(ix.indkey) WITH ORDINALITY AS k(attnum, ord) ON true
+	JOIN pg_attribute AS a ON a.attrelid = t.oid AND a.attnum = k.attnum
+	LEFT JOIN pg_constraint AS c ON c.conindid = ix.indexrelid AND c.contype = 'u'
+	WHERE t.relname = '%s' AND t.relkind = 'r' AND NOT ix.indisprimary
+	ORDER BY i.relname, k.ord;
+`, t.Name)
+	t.Indexes, err = loadIndexes(db, indexSQL)
+	if err != nil {
+		return err
+	}
+
+	commentSQL := fmt.Sprintf(`
+	SELECT a.attname, d.description
+	FROM pg_description AS d
+	JOIN pg_class AS t ON t.oid = d.objoid
+	LEFT JOIN pg_attribute AS a ON a.attrelid = t.oid AND a.attnum = d.objsubid AND d.objsubid > 0
+	WHERE d.classoid = 'pg_class'::regclass AND t.relname = '%s' AND t.relkind = 'r';
+`, t.Name)
+	return loadComments(db, commentSQL, t)
+}
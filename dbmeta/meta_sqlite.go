@@ -84,10 +84,68 @@ func LoadSqliteMeta(db *sql.DB, sqlType, sqlDatabase, tableName string) (DbTable
 		m.columns[i] = colMeta
 	}
 
+	foreignKeys, err := sqliteLoadForeignKeys(db, tableName)
+	if err != nil {
+		fmt.Printf("error calling sqliteLoadForeignKeys table: %s error: %v\n", tableName, err)
+	}
+	setForeignKeys(m, foreignKeys)
+
 	m = updateDefaultPrimaryKey(m)
 	return m, nil
 }
 
+// sqliteLoadForeignKeys loads the single column foreign keys of the table keyed by column name, a key without a referenced column
+// references the primary key of the referenced table
+func sqliteLoadForeignKeys(db *sql.DB, tableName string) (map[string]*ForeignKey, error) {
+	pragmaSQL := fmt.Sprintf("PRAGMA foreign_key_list('%s');", tableName)
+	res, err := db.Query(pragmaSQL)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load PRAGMA foreign_key_list %s: %v", tableName, err)
+	}
+	defer res.Close()
+
+	columns := make(map[int][]string)
+	foreignKeys := make(map[string]*ForeignKey)
+	for res.Next() {
+		var id, seq int
+		var refTable, from, onUpdate, onDelete, match string
+		var to sql.NullString
+		err = res.Scan(&id, &seq, &refTable, &from, &to, &onUpdate, &onDelete, &match)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load foreign keys from sqlite Scan: %v", err)
+		}
+
+		columns[id] = append(columns[id], from)
+		foreignKeys[from] = &ForeignKey{Table: refTable, Column: to.String}
+	}
+	res.Close()
+
+	for _, names := range columns {
+		if len(names) > 1 {
+			for _, name := range names {
+				delete(foreignKeys, name)
+			}
+		}
+	}
+
+	for _, fk := range foreignKeys {
+		if fk.Column != "" {
+			continue
+		}
+
+		colsInfos, err := sqliteLoadPragma(db, fk.Table)
+		if err != nil {
+			return nil, err
+		}
+		for _, ci := range colsInfos {
+			if ci.primaryKey == 1 {
+				fk.Column = ci.name
+			}
+		}
+	}
+	return foreignKeys, nil
+}
+
 func sqliteLoadPragma(db *sql.DB, tableName string) (colsInfos map[string]*sqliteColumnInfo, err error) {
 	pragmaSQL := fmt.Sprintf("PRAGMA table_info('%s');", tableName)
 	res, err := db.Query(pragmaSQL)
@@ -191,3 +249,75 @@ type sqliteColumnInfo struct {
 	dfltValue  interface{}
 	primaryKey int
 }
+
+// sqliteLoadTableSchema sets the declared column types, the primary key in key order and the indexes of the table, indexes sqlite
+// creates for unique constraints are loaded as constraints
+func sqliteLoadTableSchema(db *sql.DB, dbMeta DbTableMeta, t *TableSchema) error {
+	colsInfos, err := sqliteLoadPragma(db, t.Name)
+	if err != nil {
+		return err
+	}
+
+	primaryKey := make([]string, len(colsInfos))
+	for _, c := range t.Columns {
+		ci, ok := colsInfos[c.Name]
+		if !ok {
+			continue
+		}
+
+		c.Type = ci.dataType
+		if ci.primaryKey > 0 && ci.primaryKey <= len(primaryKey) {
+			primaryKey[ci.primaryKey-1] = c.Name
+		}
+	}
+
+	t.PrimaryKey = nil
+	for _, name := range primaryKey {
+		if name != "" {
+			t.PrimaryKey = append(t.PrimaryKey, name)
+		}
+	}
+
+	pragmaSQL := fmt.Sprintf("PRAGMA index_list('%s');", t.Name)
+	res, err := db.Query(pragmaSQL)
+	if err != nil {
+		return fmt.Errorf("unable to load PRAGMA index_list %s: %v", t.Name, err)
+	}
+
+	for res.Next() {
+		var seq, unique, partial int
+		var name, origin string
+		err = res.Scan(&seq, &name, &unique, &origin, &partial)
+		if err != nil {
+			res.Close()
+			return fmt.Errorf("unable to load indexes from sqlite Scan: %v", err)
+		}
+
+		if origin == "pk" {
+			continue
+		}
+		t.Indexes = append(t.Indexes, &IndexSchema{Name: name, Unique: unique == 1, Constraint: origin == "u"})
+	}
+	res.Close()
+
+	for _, idx := range t.Indexes {
+		pragmaSQL := fmt.Sprintf("PRAGMA index_info('%s');", idx.Name)
+		res, err := db.Query(pragmaSQL)
+		if err != nil {
+			return fmt.Errorf("unable to load PRAGMA index_info %s: %v", idx.Name, err)
+		}
+
+		for res.Next() {
+			var seqno, cid int
+			var name sql.NullString
+			err = res.Scan(&seqno, &cid, &name)
+			if err != nil {
+				res.Close()
+				return fmt.Errorf("unable to load index columns from sqlite Scan: %v", err)
+			}
+			idx.Columns = append(idx.Columns, name.String)
+		}
+		res.Close()
+	}
+	return nil
+}
@@ -258,3 +258,41 @@ func updateDefaultPrimaryKey(m *dbTableMeta) *dbTableMeta {
 	m.primaryKeyPos = primaryKeyPos
 	return m
 }
+
+// loadForeignKeys runs a query returning the constraint name, column, referenced table and referenced column of each foreign key
+// column of a table and returns the foreign keys keyed by column name, composite foreign keys are skipped
+func loadForeignKeys(db *sql.DB, foreignKeySQL string) (map[string]*ForeignKey, error) {
+	res, err := db.Query(foreignKeySQL)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load foreign keys: %v", err)
+	}
+	defer res.Close()
+
+	columns := make(map[string][]string)
+	foreignKeys := make(map[string]*ForeignKey)
+	for res.Next() {
+		var constraintName, columnName, refTable, refColumn string
+		err = res.Scan(&constraintName, &columnName, &refTable, &refColumn)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load foreign keys Scan: %v", err)
+		}
+
+		columns[constraintName] = append(columns[constraintName], columnName)
+		foreignKeys[columnName] = &ForeignKey{Name: constraintName, Table: refTable, Column: refColumn}
+	}
+
+	for _, names := range columns {
+		if len(names) > 1 {
+			for _, name := range names {
+				delete(foreignKeys, name)
+			}
+		}
+	}
+	return foreignKeys, nil
+}
+
+func setForeignKeys(m *dbTableMeta, foreignKeys map[string]*ForeignKey) {
+	for _, v := range m.columns {
+		v.foreignKey = foreignKeys[v.Name()]
+	}
+}
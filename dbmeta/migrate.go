@@ -0,0 +1,637 @@
+package dbmeta
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+)
+
+// schemaLoader loads the exact column types and the indexes of a table into a TableSchema built from the table meta data
+type schemaLoader func(db *sql.DB, dbMeta DbTableMeta, t *TableSchema) error
+
+var schemaFuncs = make(map[string]schemaLoader)
+
+func init() {
+	schemaFuncs["sqlite3"] = sqliteLoadTableSchema
+	schemaFuncs["sqlite"] = sqliteLoadTableSchema
+	schemaFuncs["mssql"] = msSQLLoadTableSchema
+	schemaFuncs["postgres"] = postgresLoadTableSchema
+	schemaFuncs["mysql"] = mysqlLoadTableSchema
+}
+
+// SchemaSnapshot schema of the tables of a database, saved by gen migrate snapshot and compared by gen migrate diff
+type SchemaSnapshot struct {
+	SQLType string         `json:"sql_type"`
+	Tables  []*TableSchema `json:"tables"`
+}
+
+// TableSchema columns, primary key, indexes and single column foreign keys of a table
+type TableSchema struct {
+	Name        string              `json:"name"`
+	Comment     string              `json:"comment,omitempty"`
+	Columns     []*ColumnSchema     `json:"columns"`
+	PrimaryKey  []string            `json:"primary_key,omitempty"`
+	Indexes     []*IndexSchema      `json:"indexes,omitempty"`
+	ForeignKeys []*ForeignKeySchema `json:"foreign_keys,omitempty"`
+}
+
+// ColumnSchema column of a table, Type is the type as declared in the ddl of the dialect
+type ColumnSchema struct {
+	Name          string `json:"name"`
+	Type          string `json:"type"`
+	Nullable      bool   `json:"nullable"`
+	Default       string `json:"default,omitempty"`
+	AutoIncrement bool   `json:"auto_increment,omitempty"`
+	Comment       string `json:"comment,omitempty"`
+}
+
+// IndexSchema index of a table, Constraint is set for unique constraints that are altered with the table rather than as an index
+type IndexSchema struct {
+	Name       string   `json:"name"`
+	Columns    []string `json:"columns"`
+	Unique     bool     `json:"unique,omitempty"`
+	Constraint bool     `json:"constraint,omitempty"`
+}
+
+// ForeignKeySchema single column foreign key constraint of a table
+type ForeignKeySchema struct {
+	Name      string `json:"name"`
+	Column    string `json:"column"`
+	RefTable  string `json:"ref_table"`
+	RefColumn string `json:"ref_column"`
+}
+
+// Migration statements migrating a database from one schema snapshot to another and back
+type Migration struct {
+	Up   []string
+	Down []string
+}
+
+// MigrationFile name and content of a migration file
+type MigrationFile struct {
+	Name    string
+	Content string
+}
+
+// LoadSchemaSnapshot loads the schema of the tables from the database, tables that can not be loaded are skipped
+func LoadSchemaSnapshot(db *sql.DB, sqlType, sqlDatabase string, dbTables []string) (*SchemaSnapshot, error) {
+	snapshot := &SchemaSnapshot{SQLType: migrationSQLType(sqlType)}
+
+	for _, tableName := range dbTables {
+		if strings.HasPrefix(tableName, "[") && strings.HasSuffix(tableName, "]") {
+			tableName = tableName[1 : len(tableName)-1]
+		}
+
+		dbMeta, err := LoadMeta(sqlType, db, sqlDatabase, tableName)
+		if err != nil {
+			fmt.Printf("Error getting table info for %s error: %v\n", tableName, err)
+			continue
+		}
+
+		t := newTableSchema(dbMeta)
+		if loader, ok := schemaFuncs[sqlType]; ok {
+			err = loader(db, dbMeta, t)
+			if err != nil {
+				return nil, fmt.Errorf("unable to load schema of table %s: %v", tableName, err)
+			}
+		}
+
+		sort.Slice(t.Indexes, func(i, j int) bool { return t.Indexes[i].Name < t.Indexes[j].Name })
+		sort.Slice(t.ForeignKeys, func(i, j int) bool { return t.ForeignKeys[i].Name < t.ForeignKeys[j].Name })
+		snapshot.Tables = append(snapshot.Tables, t)
+	}
+
+	sort.Slice(snapshot.Tables, func(i, j int) bool { return snapshot.Tables[i].Name < snapshot.Tables[j].Name })
+	return snapshot, nil
+}
+
+// newTableSchema table schema from the meta data of the table, the dialect loader replaces the types with the declared types
+func newTableSchema(dbMeta DbTableMeta) *TableSchema {
+	t := &TableSchema{Name: dbMeta.TableName()}
+
+	for _, c := range dbMeta.Columns() {
+		t.Columns = append(t.Columns, &ColumnSchema{
+			Name:          c.Name(),
+			Type:          c.DatabaseTypePretty(),
+			Nullable:      c.Nullable(),
+			Default:       c.DefaultValue(),
+			AutoIncrement: c.IsAutoIncrement(),
+		})
+
+		if c.IsPrimaryKey() {
+			t.PrimaryKey = append(t.PrimaryKey, c.Name())
+		}
+
+		if fk := c.ForeignKey(); fk != nil {
+			name := fk.Name
+			if name == "" {
+				name = fmt.Sprintf("fk_%s_%s", t.Name, c.Name())
+			}
+			t.ForeignKeys = append(t.ForeignKeys, &ForeignKeySchema{Name: name, Column: c.Name(), RefTable: fk.Table, RefColumn: fk.Column})
+		}
+	}
+	return t
+}
+
+// loadIndexes runs a query returning the index name, column name, unique flag and constraint flag of each index column of a table
+// in key order
+func loadIndexes(db *sql.DB, indexSQL string) ([]*IndexSchema, error) {
+	res, err := db.Query(indexSQL)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load indexes: %v", err)
+	}
+	defer res.Close()
+
+	var indexes []*IndexSchema
+	byName := make(map[string]*IndexSchema)
+	for res.Next() {
+		var name, columnName string
+		var unique, constraint int
+		err = res.Scan(&name, &columnName, &unique, &constraint)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load indexes Scan: %v", err)
+		}
+
+		idx, ok := byName[name]
+		if !ok {
+			idx = &IndexSchema{Name: name, Unique: unique == 1, Constraint: constraint == 1}
+			byName[name] = idx
+			indexes = append(indexes, idx)
+		}
+		idx.Columns = append(idx.Columns, columnName)
+	}
+	return indexes, nil
+}
+
+// loadComments runs a query returning the column name and comment of the commented columns of a table, the comment of the
+// table itself is returned with an empty column name
+func loadComments(db *sql.DB, commentSQL string, t *TableSchema) error {
+	res, err := db.Query(commentSQL)
+	if err != nil {
+		return fmt.Errorf("unable to load comments: %v", err)
+	}
+	defer res.Close()
+
+	for res.Next() {
+		var columnName, comment sql.NullString
+		err = res.Scan(&columnName, &comment)
+		if err != nil {
+			return fmt.Errorf("unable to load comments Scan: %v", err)
+		}
+
+		if columnName.String == "" {
+			t.Comment = comment.String
+		} else if c := t.column(columnName.String); c != nil {
+			c.Comment = comment.String
+		}
+	}
+	return nil
+}
+
+// Table table of the snapshot, nil when the snapshot does not have the table
+func (s *SchemaSnapshot) Table(name string) *TableSchema {
+	for _, t := range s.Tables {
+		if t.Name == name {
+			return t
+		}
+	}
+	return nil
+}
+
+// Save writes the snapshot as indented json
+func (s *SchemaSnapshot) Save(fileName string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to marshal schema snapshot: %v", err)
+	}
+	return ioutil.WriteFile(fileName, append(data, '\n'), 0644)
+}
+
+// LoadSchemaSnapshotFile reads a snapshot saved with Save
+func LoadSchemaSnapshotFile(fileName string) (*SchemaSnapshot, error) {
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &SchemaSnapshot{}
+	err = json.Unmarshal(data, snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse schema snapshot %s: %v", fileName, err)
+	}
+	return snapshot, nil
+}
+
+func migrationSQLType(sqlType string) string {
+	if sqlType == "sqlite" {
+		return "sqlite3"
+	}
+	return sqlType
+}
+
+// DiffSchemaSnapshots migration from the schema of from to the schema of to, the statements are written for the dialect of the
+// snapshots which must match
+func DiffSchemaSnapshots(from, to *SchemaSnapshot) (*Migration, error) {
+	if migrationSQLType(from.SQLType) != migrationSQLType(to.SQLType) {
+		return nil, fmt.Errorf("unable to diff a %s schema against a %s schema", from.SQLType, to.SQLType)
+	}
+
+	d := migrationDialect(migrationSQLType(to.SQLType))
+	return &Migration{Up: d.diff(from, to), Down: d.diff(to, from)}, nil
+}
+
+// Empty returns true when the schemas are the same
+func (m *Migration) Empty() bool {
+	return len(m.Up) == 0 && len(m.Down) == 0
+}
+
+// Files migration files of the migration in the golang-migrate or goose format
+// params - format  - golang-migrate writes a .up.sql and a .down.sql file, goose writes a single .sql file with annotations
+// params - version - version prefix of the file names, such as a timestamp
+// params - name    - name of the migration
+func (m *Migration) Files(format, version, name string) ([]*MigrationFile, error) {
+	baseName := fmt.Sprintf("%s_%s", version, name)
+
+	switch format {
+	case "golang-migrate":
+		return []*MigrationFile{
+			{Name: baseName + ".up.sql", Content: migrationSQL(m.Up)},
+			{Name: baseName + ".down.sql", Content: migrationSQL(m.Down)},
+		}, nil
+	case "goose":
+		content := "-- +goose Up\n" + migrationSQL(m.Up) + "\n-- +goose Down\n" + migrationSQL(m.Down)
+		return []*MigrationFile{{Name: baseName + ".sql", Content: content}}, nil
+	}
+	return nil, fmt.Errorf("unknown migration format: %s, must be one of [ golang-migrate | goose ]", format)
+}
+
+func migrationSQL(statements []string) string {
+	if len(statements) == 0 {
+		return ""
+	}
+	return strings.Join(statements, "\n") + "\n"
+}
+
+// migrationDialect sql type the migration statements are written for
+type migrationDialect string
+
+func (d migrationDialect) quote(name string) string {
+	switch d {
+	case "mysql":
+		return "`" + name + "`"
+	case "mssql":
+		return "[" + name + "]"
+	}
+	return `"` + name + `"`
+}
+
+func (d migrationDialect) quoteList(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = d.quote(name)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// diff statements migrating the from schema to the to schema, ordered so that constraints are dropped before the columns and
+// tables they use and added after them
+func (d migrationDialect) diff(from, to *SchemaSnapshot) []string {
+	var dropConstraints, createTables, alterTables, dropTables, addConstraints []string
+
+	for _, ft := range from.Tables {
+		tt := to.Table(ft.Name)
+		if tt == nil {
+			if d != "sqlite3" {
+				for _, fk := range ft.ForeignKeys {
+					dropConstraints = append(dropConstraints, d.dropForeignKey(ft, fk))
+				}
+			}
+			dropTables = append(dropTables, fmt.Sprintf("DROP TABLE %s;", d.quote(ft.Name)))
+			continue
+		}
+
+		for _, fk := range ft.ForeignKeys {
+			if !foreignKeySchemaEqual(fk, tt.foreignKey(fk.Name)) {
+				dropConstraints = append(dropConstraints, d.dropForeignKey(ft, fk))
+			}
+		}
+		for _, idx := range ft.Indexes {
+			if !indexSchemaEqual(idx, tt.index(idx.Name)) {
+				dropConstraints = append(dropConstraints, d.dropIndex(ft, idx))
+			}
+		}
+	}
+
+	for _, tt := range to.Tables {
+		ft := from.Table(tt.Name)
+		if ft == nil {
+			createTables = append(createTables, d.createTable(tt))
+			for _, idx := range tt.Indexes {
+				if d != "sqlite3" || !idx.Constraint {
+					addConstraints = append(addConstraints, d.createIndex(tt, idx))
+				}
+			}
+			if d != "sqlite3" {
+				for _, fk := range tt.ForeignKeys {
+					addConstraints = append(addConstraints, d.addForeignKey(tt, fk))
+				}
+			}
+			continue
+		}
+
+		alterTables = append(alterTables, d.alterTable(ft, tt)...)
+		for _, idx := range tt.Indexes {
+			if !indexSchemaEqual(idx, ft.index(idx.Name)) {
+				addConstraints = append(addConstraints, d.createIndex(tt, idx))
+			}
+		}
+		for _, fk := range tt.ForeignKeys {
+			if !foreignKeySchemaEqual(fk, ft.foreignKey(fk.Name)) {
+				addConstraints = append(addConstraints, d.addForeignKey(tt, fk))
+			}
+		}
+	}
+
+	var statements []string
+	for _, group := range [][]string{dropConstraints, createTables, alterTables, dropTables, addConstraints} {
+		statements = append(statements, group...)
+	}
+	return statements
+}
+
+// alterTable statements adding, altering and dropping the columns of a table and changing its primary key
+func (d migrationDialect) alterTable(from, to *TableSchema) []string {
+	var statements []string
+	table := d.quote(to.Name)
+
+	for _, c := range to.Columns {
+		fc := from.column(c.Name)
+		if fc == nil {
+			if d == "mssql" {
+				statements = append(statements, fmt.Sprintf("ALTER TABLE %s ADD %s;", table, d.columnDefinition(c)))
+			} else {
+				statements = append(statements, fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s;", table, d.columnDefinition(c)))
+			}
+			continue
+		}
+
+		if !columnSchemaEqual(fc, c) {
+			statements = append(statements, d.alterColumn(to, fc, c)...)
+		}
+	}
+
+	if strings.Join(from.PrimaryKey, ",") != strings.Join(to.PrimaryKey, ",") {
+		statements = append(statements, d.alterPrimaryKey(from, to)...)
+	}
+
+	for _, fc := range from.Columns {
+		if to.column(fc.Name) == nil {
+			statements = append(statements, fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s;", table, d.quote(fc.Name)))
+		}
+	}
+	return statements
+}
+
+// columnDefinition definition of a column as used in create table and add column statements
+func (d migrationDialect) columnDefinition(c *ColumnSchema) string {
+	def := d.quote(c.Name) + " " + c.Type
+
+	if c.AutoIncrement {
+		switch d {
+		case "postgres":
+			def += " GENERATED BY DEFAULT AS IDENTITY"
+		case "mssql":
+			def += " IDENTITY(1,1)"
+		}
+	}
+
+	if !c.Nullable {
+		def += " NOT NULL"
+	} else if d == "mysql" || d == "mssql" {
+		def += " NULL"
+	}
+
+	if c.Default != "" && !(c.AutoIncrement && d == "postgres") {
+		def += " DEFAULT " + c.Default
+	}
+
+	if c.AutoIncrement && d == "mysql" {
+		def += " AUTO_INCREMENT"
+	}
+	return def
+}
+
+// createTable create table statement, sqlite declares the unique constraints and foreign keys in the table as it can not add them later
+func (d migrationDialect) createTable(t *TableSchema) string {
+	var lines []string
+	rowID := ""
+	if d == "sqlite3" && len(t.PrimaryKey) == 1 {
+		if c := t.column(t.PrimaryKey[0]); c != nil && c.AutoIncrement {
+			rowID = c.Name
+		}
+	}
+
+	for _, c := range t.Columns {
+		if c.Name == rowID {
+			lines = append(lines, fmt.Sprintf("%s %s PRIMARY KEY AUTOINCREMENT", d.quote(c.Name), c.Type))
+			continue
+		}
+		lines = append(lines, d.columnDefinition(c))
+	}
+
+	if len(t.PrimaryKey) > 0 && rowID == "" {
+		lines = append(lines, fmt.Sprintf("PRIMARY KEY (%s)", d.quoteList(t.PrimaryKey)))
+	}
+
+	if d == "sqlite3" {
+		for _, idx := range t.Indexes {
+			if idx.Constraint {
+				lines = append(lines, fmt.Sprintf("UNIQUE (%s)", d.quoteList(idx.Columns)))
+			}
+		}
+		for _, fk := range t.ForeignKeys {
+			lines = append(lines, fmt.Sprintf("FOREIGN KEY (%s) REFERENCES %s (%s)", d.quote(fk.Column), d.quote(fk.RefTable), d.quote(fk.RefColumn)))
+		}
+	}
+
+	return fmt.Sprintf("CREATE TABLE %s (\n\t%s\n);", d.quote(t.Name), strings.Join(lines, ",\n\t"))
+}
+
+// alterColumn statements changing the type, nullability, default or auto increment of a column
+func (d migrationDialect) alterColumn(t *TableSchema, from, to *ColumnSchema) []string {
+	table := d.quote(t.Name)
+	column := d.quote(to.Name)
+
+	switch d {
+	case "mysql":
+		return []string{fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s;", table, d.columnDefinition(to))}
+
+	case "postgres":
+		var statements []string
+		if !strings.EqualFold(from.Type, to.Type) {
+			statements = append(statements, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s TYPE %s USING %s::%s;", table, column, to.Type, column, to.Type))
+		}
+		if from.AutoIncrement && !to.AutoIncrement {
+			statements = append(statements, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s DROP IDENTITY IF EXISTS;", table, column))
+		}
+		if from.Nullable != to.Nullable {
+			if to.Nullable {
+				statements = append(statements, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s DROP NOT NULL;", table, column))
+			} else {
+				statements = append(statements, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET NOT NULL;", table, column))
+			}
+		}
+		if from.Default != to.Default {
+			if to.Default == "" {
+				statements = append(statements, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s DROP DEFAULT;", table, column))
+			} else {
+				statements = append(statements, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET DEFAULT %s;", table, column, to.Default))
+			}
+		}
+		if !from.AutoIncrement && to.AutoIncrement {
+			statements = append(statements, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s ADD GENERATED BY DEFAULT AS IDENTITY;", table, column))
+		}
+		return statements
+
+	case "mssql":
+		var statements []string
+		if !strings.EqualFold(from.Type, to.Type) || from.Nullable != to.Nullable {
+			nullable := " NULL"
+			if !to.Nullable {
+				nullable = " NOT NULL"
+			}
+			statements = append(statements, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s %s%s;", table, column, to.Type, nullable))
+		}
+		if from.Default != to.Default {
+			if from.Default == "" {
+				statements = append(statements, fmt.Sprintf("ALTER TABLE %s ADD DEFAULT %s FOR %s;", table, to.Default, column))
+			} else {
+				statements = append(statements, fmt.Sprintf("-- default of %s.%s changed from %s to %s, sql server defaults are named constraints, alter it by hand",
+					table, column, from.Default, to.Default))
+			}
+		}
+		if from.AutoIncrement != to.AutoIncrement {
+			statements = append(statements, fmt.Sprintf("-- identity of %s.%s changed, sql server can not alter the identity of a column, rebuild the table by hand",
+				table, column))
+		}
+		return statements
+	}
+
+	return []string{fmt.Sprintf("-- %s can not alter column %s.%s from %s to %s, rebuild the table by hand",
+		d, table, column, d.columnDefinition(from), d.columnDefinition(to))}
+}
+
+// alterPrimaryKey statements replacing the primary key of a table, postgres primary keys are assumed to have the default name
+func (d migrationDialect) alterPrimaryKey(from, to *TableSchema) []string {
+	var statements []string
+	table := d.quote(to.Name)
+
+	switch d {
+	case "mysql", "postgres":
+		if len(from.PrimaryKey) > 0 {
+			if d == "mysql" {
+				statements = append(statements, fmt.Sprintf("ALTER TABLE %s DROP PRIMARY KEY;", table))
+			} else {
+				statements = append(statements, fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s;", table, d.quote(to.Name+"_pkey")))
+			}
+		}
+		if len(to.PrimaryKey) > 0 {
+			statements = append(statements, fmt.Sprintf("ALTER TABLE %s ADD PRIMARY KEY (%s);", table, d.quoteList(to.PrimaryKey)))
+		}
+	default:
+		statements = append(statements, fmt.Sprintf("-- primary key of %s changed from (%s) to (%s), %s can not replace it, alter it by hand",
+			table, d.quoteList(from.PrimaryKey), d.quoteList(to.PrimaryKey), d))
+	}
+	return statements
+}
+
+func (d migrationDialect) createIndex(t *TableSchema, idx *IndexSchema) string {
+	if idx.Constraint {
+		if d == "sqlite3" {
+			return fmt.Sprintf("-- sqlite can not add the unique constraint %s to %s, rebuild the table by hand", d.quote(idx.Name), d.quote(t.Name))
+		}
+		return fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s UNIQUE (%s);", d.quote(t.Name), d.quote(idx.Name), d.quoteList(idx.Columns))
+	}
+
+	unique := ""
+	if idx.Unique {
+		unique = "UNIQUE "
+	}
+	return fmt.Sprintf("CREATE %sINDEX %s ON %s (%s);", unique, d.quote(idx.Name), d.quote(t.Name), d.quoteList(idx.Columns))
+}
+
+func (d migrationDialect) dropIndex(t *TableSchema, idx *IndexSchema) string {
+	if idx.Constraint {
+		if d == "sqlite3" {
+			return fmt.Sprintf("-- sqlite can not drop the unique constraint %s of %s, rebuild the table by hand", d.quote(idx.Name), d.quote(t.Name))
+		}
+		return fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s;", d.quote(t.Name), d.quote(idx.Name))
+	}
+
+	if d == "mysql" || d == "mssql" {
+		return fmt.Sprintf("DROP INDEX %s ON %s;", d.quote(idx.Name), d.quote(t.Name))
+	}
+	return fmt.Sprintf("DROP INDEX %s;", d.quote(idx.Name))
+}
+
+func (d migrationDialect) addForeignKey(t *TableSchema, fk *ForeignKeySchema) string {
+	if d == "sqlite3" {
+		return fmt.Sprintf("-- sqlite can not add the foreign key %s.%s -> %s.%s, rebuild the table by hand",
+			d.quote(t.Name), d.quote(fk.Column), d.quote(fk.RefTable), d.quote(fk.RefColumn))
+	}
+	return fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s (%s);",
+		d.quote(t.Name), d.quote(fk.Name), d.quote(fk.Column), d.quote(fk.RefTable), d.quote(fk.RefColumn))
+}
+
+func (d migrationDialect) dropForeignKey(t *TableSchema, fk *ForeignKeySchema) string {
+	switch d {
+	case "sqlite3":
+		return fmt.Sprintf("-- sqlite can not drop the foreign key %s.%s -> %s.%s, rebuild the table by hand",
+			d.quote(t.Name), d.quote(fk.Column), d.quote(fk.RefTable), d.quote(fk.RefColumn))
+	case "mysql":
+		return fmt.Sprintf("ALTER TABLE %s DROP FOREIGN KEY %s;", d.quote(t.Name), d.quote(fk.Name))
+	}
+	return fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s;", d.quote(t.Name), d.quote(fk.Name))
+}
+
+func (t *TableSchema) column(name string) *ColumnSchema {
+	for _, c := range t.Columns {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+func (t *TableSchema) index(name string) *IndexSchema {
+	for _, idx := range t.Indexes {
+		if idx.Name == name {
+			return idx
+		}
+	}
+	return nil
+}
+
+func (t *TableSchema) foreignKey(name string) *ForeignKeySchema {
+	for _, fk := range t.ForeignKeys {
+		if fk.Name == name {
+			return fk
+		}
+	}
+	return nil
+}
+
+func columnSchemaEqual(a, b *ColumnSchema) bool {
+	return strings.EqualFold(a.Type, b.Type) && a.Nullable == b.Nullable && a.Default == b.Default && a.AutoIncrement == b.AutoIncrement
+}
+
+func indexSchemaEqual(a, b *IndexSchema) bool {
+	return b != nil && a.Unique == b.Unique && a.Constraint == b.Constraint && strings.Join(a.Columns, ",") == strings.Join(b.Columns, ",")
+}
+
+func foreignKeySchemaEqual(a, b *ForeignKeySchema) bool {
+	return b != nil && a.Column == b.Column && a.RefTable == b.RefTable && a.RefColumn == b.RefColumn
+}
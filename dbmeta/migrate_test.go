@@ -0,0 +1,160 @@
+package dbmeta
+
+import (
+	"strings"
+	"testing"
+)
+
+func migrateTestSnapshots(sqlType string) (from, to *SchemaSnapshot) {
+	from = &SchemaSnapshot{SQLType: sqlType, Tables: []*TableSchema{
+		{
+			Name: "artists",
+			Columns: []*ColumnSchema{
+				{Name: "id", Type: "int", AutoIncrement: true},
+				{Name: "name", Type: "varchar(120)", Nullable: true},
+			},
+			PrimaryKey: []string{"id"},
+		},
+		{
+			Name: "albums",
+			Columns: []*ColumnSchema{
+				{Name: "id", Type: "int"},
+				{Name: "artist_id", Type: "int"},
+			},
+			PrimaryKey:  []string{"id"},
+			ForeignKeys: []*ForeignKeySchema{{Name: "fk_artist", Column: "artist_id", RefTable: "artists", RefColumn: "id"}},
+		},
+	}}
+
+	to = &SchemaSnapshot{SQLType: sqlType, Tables: []*TableSchema{
+		{
+			Name: "artists",
+			Columns: []*ColumnSchema{
+				{Name: "id", Type: "int", AutoIncrement: true},
+				{Name: "name", Type: "varchar(200)"},
+				{Name: "country", Type: "varchar(40)", Nullable: true, Default: "'NZ'"},
+			},
+			PrimaryKey: []string{"id"},
+			Indexes:    []*IndexSchema{{Name: "uq_name", Columns: []string{"name"}, Unique: true}},
+		},
+	}}
+	return from, to
+}
+
+func Test_DiffSchemaSnapshots(t *testing.T) {
+	tests := []struct {
+		sqlType string
+		up      string
+		down    string
+	}{
+		{
+			"mysql",
+			"ALTER TABLE `albums` DROP FOREIGN KEY `fk_artist`;\n" +
+				"ALTER TABLE `artists` MODIFY COLUMN `name` varchar(200) NOT NULL;\n" +
+				"ALTER TABLE `artists` ADD COLUMN `country` varchar(40) NULL DEFAULT 'NZ';\n" +
+				"DROP TABLE `albums`;\n" +
+				"CREATE UNIQUE INDEX `uq_name` ON `artists` (`name`);",
+			"DROP INDEX `uq_name` ON `artists`;\n" +
+				"CREATE TABLE `albums` (\n\t`id` int NOT NULL,\n\t`artist_id` int NOT NULL,\n\tPRIMARY KEY (`id`)\n);\n" +
+				"ALTER TABLE `artists` MODIFY COLUMN `name` varchar(120) NULL;\n" +
+				"ALTER TABLE `artists` DROP COLUMN `country`;\n" +
+				"ALTER TABLE `albums` ADD CONSTRAINT `fk_artist` FOREIGN KEY (`artist_id`) REFERENCES `artists` (`id`);",
+		},
+		{
+			"postgres",
+			"ALTER TABLE \"albums\" DROP CONSTRAINT \"fk_artist\";\n" +
+				"ALTER TABLE \"artists\" ALTER COLUMN \"name\" TYPE varchar(200) USING \"name\"::varchar(200);\n" +
+				"ALTER TABLE \"artists\" ALTER COLUMN \"name\" SET NOT NULL;\n" +
+				"ALTER TABLE \"artists\" ADD COLUMN \"country\" varchar(40) DEFAULT 'NZ';\n" +
+				"DROP TABLE \"albums\";\n" +
+				"CREATE UNIQUE INDEX \"uq_name\" ON \"artists\" (\"name\");",
+			"DROP INDEX \"uq_name\";\n" +
+				"CREATE TABLE \"albums\" (\n\t\"id\" int NOT NULL,\n\t\"artist_id\" int NOT NULL,\n\tPRIMARY KEY (\"id\")\n);\n" +
+				"ALTER TABLE \"artists\" ALTER COLUMN \"name\" TYPE varchar(120) USING \"name\"::varchar(120);\n" +
+				"ALTER TABLE \"artists\" ALTER COLUMN \"name\" DROP NOT NULL;\n" +
+				"ALTER TABLE \"artists\" DROP COLUMN \"country\";\n" +
+				"ALTER TABLE \"albums\" ADD CONSTRAINT \"fk_artist\" FOREIGN KEY (\"artist_id\") REFERENCES \"artists\" (\"id\");",
+		},
+		{
+			"sqlite3",
+			"-- sqlite3 can not alter column \"artists\".\"name\" from \"name\" varchar(120) to \"name\" varchar(200) NOT NULL, rebuild the table by hand\n" +
+				"ALTER TABLE \"artists\" ADD COLUMN \"country\" varchar(40) DEFAULT 'NZ';\n" +
+				"DROP TABLE \"albums\";\n" +
+				"CREATE UNIQUE INDEX \"uq_name\" ON \"artists\" (\"name\");",
+			"DROP INDEX \"uq_name\";\n" +
+				"CREATE TABLE \"albums\" (\n\t\"id\" int NOT NULL,\n\t\"artist_id\" int NOT NULL,\n\tPRIMARY KEY (\"id\"),\n\tFOREIGN KEY (\"artist_id\") REFERENCES \"artists\" (\"id\")\n);\n" +
+				"-- sqlite3 can not alter column \"artists\".\"name\" from \"name\" varchar(200) NOT NULL to \"name\" varchar(120), rebuild the table by hand\n" +
+				"ALTER TABLE \"artists\" DROP COLUMN \"country\";",
+		},
+	}
+
+	for _, tt := range tests {
+		from, to := migrateTestSnapshots(tt.sqlType)
+		migration, err := DiffSchemaSnapshots(from, to)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.sqlType, err)
+			continue
+		}
+
+		up := strings.Join(migration.Up, "\n")
+		if up != tt.up {
+			t.Errorf("%s: expect up: %s, but got %s", tt.sqlType, tt.up, up)
+		}
+		down := strings.Join(migration.Down, "\n")
+		if down != tt.down {
+			t.Errorf("%s: expect down: %s, but got %s", tt.sqlType, tt.down, down)
+		}
+	}
+}
+
+func Test_MigrationFiles(t *testing.T) {
+	migration := &Migration{Up: []string{"DROP TABLE \"albums\";"}, Down: []string{"CREATE TABLE \"albums\" (\"id\" int);"}}
+
+	files, err := migration.Files("goose", "20200601120000", "drop_albums")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "-- +goose Up\nDROP TABLE \"albums\";\n\n-- +goose Down\nCREATE TABLE \"albums\" (\"id\" int);\n"
+	if len(files) != 1 || files[0].Name != "20200601120000_drop_albums.sql" || files[0].Content != expected {
+		t.Errorf("goose: expect: %s, but got %v", expected, files)
+	}
+
+	files, err = migration.Files("golang-migrate", "20200601120000", "drop_albums")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(files) != 2 || files[0].Name != "20200601120000_drop_albums.up.sql" || files[1].Name != "20200601120000_drop_albums.down.sql" {
+		t.Errorf("golang-migrate: expect up and down files, but got %v", files)
+	}
+
+	_, err = migration.Files("flyway", "1", "drop_albums")
+	if err == nil {
+		t.Errorf("flyway: expect an error")
+	}
+}
+
+func Test_mysqlParseColumn(t *testing.T) {
+	tests := []struct {
+		colDDL      string
+		columnType  string
+		defaultText string
+	}{
+		{"int(10) unsigned NOT NULL AUTO_INCREMENT", "int(10) unsigned", ""},
+		{"varchar(20) CHARACTER SET utf8 NOT NULL DEFAULT 'it''s'", "varchar(20)", "'it''s'"},
+		{"enum('G','PG 13') DEFAULT 'G'", "enum('G','PG 13')", "'G'"},
+		{"decimal(4,2) DEFAULT NULL", "decimal(4,2)", ""},
+		{"timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP", "timestamp", "CURRENT_TIMESTAMP"},
+	}
+
+	for _, tt := range tests {
+		columnType := mysqlParseColumnType(tt.colDDL)
+		if columnType != tt.columnType {
+			t.Errorf("%s: expect: %s, but got %s", tt.colDDL, tt.columnType, columnType)
+		}
+		defaultText := mysqlParseColumnDefault(tt.colDDL)
+		if defaultText != tt.defaultText {
+			t.Errorf("%s: expect: %s, but got %s", tt.colDDL, tt.defaultText, defaultText)
+		}
+	}
+}
@@ -0,0 +1,476 @@
+package dbmeta
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jinzhu/inflection"
+	"gopkg.in/yaml.v2"
+)
+
+// GenerateOpenAPI generate an OpenAPI 3.0 spec in yaml for the rest api generated for the tables
+func (c *Config) GenerateOpenAPI(tableInfos map[string]*ModelInfo) ([]byte, error) {
+	tableNames := make([]string, 0, len(tableInfos))
+	for tableName, tableInfo := range tableInfos {
+		if len(tableInfo.Fields) == 0 || PrimaryKeyCount(tableInfo.DBMeta) == 0 {
+			continue
+		}
+		tableNames = append(tableNames, tableName)
+	}
+	sort.Strings(tableNames)
+
+	paths := yaml.MapSlice{}
+	schemas := yaml.MapSlice{
+		{Key: "HTTPError", Value: yaml.MapSlice{
+			{Key: "type", Value: "object"},
+			{Key: "description", Value: "RFC 7807 problem details"},
+			{Key: "properties", Value: yaml.MapSlice{
+				{Key: "type", Value: yaml.MapSlice{{Key: "type", Value: "string"}, {Key: "example", Value: "about:blank"}}},
+				{Key: "title", Value: yaml.MapSlice{{Key: "type", Value: "string"}, {Key: "example", Value: "Not Found"}}},
+				{Key: "status", Value: yaml.MapSlice{{Key: "type", Value: "integer"}, {Key: "example", Value: 404}}},
+				{Key: "detail", Value: yaml.MapSlice{{Key: "type", Value: "string"}, {Key: "example", Value: "record Not Found"}}},
+				{Key: "instance", Value: yaml.MapSlice{{Key: "type", Value: "string"}}},
+				{Key: "errors", Value: yaml.MapSlice{
+					{Key: "type", Value: "array"},
+					{Key: "items", Value: yaml.MapSlice{{Key: "$ref", Value: "#/components/schemas/FieldError"}}},
+				}},
+			}},
+		}},
+		{Key: "FieldError", Value: yaml.MapSlice{
+			{Key: "type", Value: "object"},
+			{Key: "properties", Value: yaml.MapSlice{
+				{Key: "field", Value: yaml.MapSlice{{Key: "type", Value: "string"}, {Key: "example", Value: "name"}}},
+				{Key: "message", Value: yaml.MapSlice{{Key: "type", Value: "string"}, {Key: "example", Value: "is required"}}},
+			}},
+		}},
+	}
+
+	router, err := LookupRouter(c.Router)
+	if err != nil {
+		return nil, err
+	}
+	if c.GenerateImportExport {
+		schemas = append(schemas, yaml.MapItem{Key: "ImportResult", Value: yaml.MapSlice{
+			{Key: "type", Value: "object"},
+			{Key: "properties", Value: yaml.MapSlice{
+				{Key: "imported", Value: yaml.MapSlice{{Key: "type", Value: "integer"}, {Key: "example", Value: 100}}},
+			}},
+		}})
+	}
+
+	for _, tableName := range tableNames {
+		tableInfo := tableInfos[tableName]
+		paths = append(paths, openAPIPaths(tableInfo)...)
+		paths = append(paths, openAPIStreamPath(tableInfo, router))
+		if c.GenerateImportExport {
+			paths = append(paths, openAPIImportExportPaths(tableInfo, router)...)
+		}
+		schemas = append(schemas, yaml.MapItem{Key: tableInfo.StructName, Value: openAPIModelSchema(tableInfo)})
+	}
+
+	info := yaml.MapSlice{
+		{Key: "title", Value: c.Swagger.Title},
+		{Key: "description", Value: c.Swagger.Description},
+		{Key: "version", Value: c.Swagger.Version},
+	}
+	if c.Swagger.TOS != "" {
+		info = append(info, yaml.MapItem{Key: "termsOfService", Value: c.Swagger.TOS})
+	}
+
+	contact := yaml.MapSlice{}
+	if c.Swagger.ContactName != "" {
+		contact = append(contact, yaml.MapItem{Key: "name", Value: c.Swagger.ContactName})
+	}
+	if c.Swagger.ContactURL != "" {
+		contact = append(contact, yaml.MapItem{Key: "url", Value: c.Swagger.ContactURL})
+	}
+	if c.Swagger.ContactEmail != "" {
+		contact = append(contact, yaml.MapItem{Key: "email", Value: c.Swagger.ContactEmail})
+	}
+	if len(contact) > 0 {
+		info = append(info, yaml.MapItem{Key: "contact", Value: contact})
+	}
+	info = append(info, yaml.MapItem{Key: "license", Value: yaml.MapSlice{
+		{Key: "name", Value: "Apache 2.0"},
+		{Key: "url", Value: "http://www.apache.org/licenses/LICENSE-2.0.html"},
+	}})
+
+	doc := yaml.MapSlice{
+		{Key: "openapi", Value: "3.0.3"},
+		{Key: "info", Value: info},
+		{Key: "servers", Value: []yaml.MapSlice{
+			{{Key: "url", Value: fmt.Sprintf("http://%s:%d%s", c.ServerHost, c.ServerPort, strings.TrimSuffix(c.Swagger.BasePath, "/"))}},
+		}},
+		{Key: "paths", Value: paths},
+		{Key: "components", Value: yaml.MapSlice{{Key: "schemas", Value: schemas}}},
+	}
+
+	return yaml.Marshal(doc)
+}
+
+func openAPIPaths(tableInfo *ModelInfo) yaml.MapSlice {
+	structName := tableInfo.StructName
+	collection := "/" + strings.ToLower(inflection.Plural(structName))
+	ref := yaml.MapSlice{{Key: "$ref", Value: "#/components/schemas/" + structName}}
+
+	item := collection
+	var keyParams []yaml.MapSlice
+	for _, f := range tableInfo.CodeFields {
+		if f.PrimaryKeyArgName == "" {
+			continue
+		}
+		item = fmt.Sprintf("%s/{%s}", item, f.PrimaryKeyArgName)
+
+		typ, format := openAPIType(f)
+		schema := yaml.MapSlice{{Key: "type", Value: typ}}
+		if format != "" {
+			schema = append(schema, yaml.MapItem{Key: "format", Value: format})
+		}
+
+		keyParams = append(keyParams, yaml.MapSlice{
+			{Key: "name", Value: f.PrimaryKeyArgName},
+			{Key: "in", Value: "path"},
+			{Key: "required", Value: true},
+			{Key: "description", Value: f.ColumnMeta.Name()},
+			{Key: "schema", Value: schema},
+		})
+	}
+
+	includeDeleted := yaml.MapSlice{
+		{Key: "name", Value: "include_deleted"},
+		{Key: "in", Value: "query"},
+		{Key: "description", Value: "include soft deleted records"},
+		{Key: "schema", Value: yaml.MapSlice{{Key: "type", Value: "boolean"}}},
+	}
+	ifMatch := yaml.MapSlice{
+		{Key: "name", Value: "If-Match"},
+		{Key: "in", Value: "header"},
+		{Key: "description", Value: "versions of the record, from the ETag header, or * to match any version"},
+		{Key: "schema", Value: yaml.MapSlice{{Key: "type", Value: "string"}}},
+	}
+
+	listParams := []yaml.MapSlice{
+		openAPIQueryParam("page", "integer", "page requested (defaults to 0)"),
+		openAPIQueryParam("pagesize", "integer", "number of records in a page  (defaults to 20)"),
+		openAPIQueryParam("order", "string", "db sort order column"),
+	}
+	getParams := keyParams
+	writeParams := keyParams
+	if tableInfo.SoftDeleteField != nil {
+		listParams = append(listParams, includeDeleted)
+		getParams = append(append([]yaml.MapSlice{}, keyParams...), includeDeleted)
+	}
+	if tableInfo.VersionField != nil {
+		writeParams = append(append([]yaml.MapSlice{}, keyParams...), ifMatch)
+	}
+	patchParams := append(append([]yaml.MapSlice{}, writeParams...),
+		openAPIQueryParam("fields", "string", "comma separated list of fields to update, defaults to the fields in the body"))
+
+	body := yaml.MapSlice{
+		{Key: "required", Value: true},
+		{Key: "content", Value: yaml.MapSlice{{Key: "application/json", Value: yaml.MapSlice{{Key: "schema", Value: ref}}}}},
+	}
+	patchBody := yaml.MapSlice{
+		{Key: "required", Value: true},
+		{Key: "content", Value: yaml.MapSlice{
+			{Key: "application/merge-patch+json", Value: yaml.MapSlice{{Key: "schema", Value: ref}}},
+			{Key: "application/json", Value: yaml.MapSlice{{Key: "schema", Value: ref}}},
+		}},
+	}
+
+	record := openAPIResponse("OK", ref)
+	rowsAffected := openAPIResponse("number of rows affected", yaml.MapSlice{{Key: "type", Value: "integer"}, {Key: "format", Value: "int64"}})
+	page := openAPIResponse("OK", yaml.MapSlice{
+		{Key: "type", Value: "object"},
+		{Key: "properties", Value: yaml.MapSlice{
+			{Key: "page", Value: yaml.MapSlice{{Key: "type", Value: "integer"}, {Key: "format", Value: "int64"}}},
+			{Key: "page_size", Value: yaml.MapSlice{{Key: "type", Value: "integer"}, {Key: "format", Value: "int64"}}},
+			{Key: "data", Value: yaml.MapSlice{{Key: "type", Value: "array"}, {Key: "items", Value: ref}}},
+			{Key: "total_records", Value: yaml.MapSlice{{Key: "type", Value: "integer"}}},
+		}},
+	})
+
+	badRequest := yaml.MapItem{Key: "400", Value: openAPIError("bad request")}
+	notFound := yaml.MapItem{Key: "404", Value: openAPIError("record not found")}
+	conflict := yaml.MapItem{Key: "409", Value: openAPIError("a unique or foreign key constraint was violated")}
+	invalid := yaml.MapItem{Key: "422", Value: openAPIError("validation failed, errors lists the fields")}
+	serverError := yaml.MapItem{Key: "500", Value: openAPIError("internal server error")}
+
+	writeConflict := conflict
+	if tableInfo.VersionField != nil {
+		writeConflict = yaml.MapItem{Key: "409", Value: openAPIError("record has been updated since it was read or a constraint was violated")}
+	}
+	writeResponses := yaml.MapSlice{{Key: "200", Value: record}, badRequest, notFound, writeConflict, invalid, serverError}
+
+	paths := yaml.MapSlice{
+		{Key: collection, Value: yaml.MapSlice{
+			{Key: "get", Value: openAPIOperation(structName, "GetAll"+inflection.Plural(structName), "Get list of "+structName, listParams, nil,
+				yaml.MapSlice{{Key: "200", Value: page}, badRequest, serverError})},
+			{Key: "post", Value: openAPIOperation(structName, "Add"+structName, "Add a record to "+tableInfo.TableName, nil, body,
+				yaml.MapSlice{{Key: "201", Value: openAPIResponse("Created", ref)}, badRequest, conflict, invalid, serverError})},
+		}},
+		{Key: item, Value: yaml.MapSlice{
+			{Key: "get", Value: openAPIOperation(structName, "Get"+structName, "Get a record from "+tableInfo.TableName, getParams, nil,
+				yaml.MapSlice{{Key: "200", Value: record}, badRequest, notFound, serverError})},
+			{Key: "put", Value: openAPIOperation(structName, "Update"+structName, "Update a record in "+tableInfo.TableName, writeParams, body, writeResponses)},
+			{Key: "patch", Value: openAPIOperation(structName, "Patch"+structName, "Partially update a record in "+tableInfo.TableName, patchParams, patchBody, writeResponses)},
+			{Key: "delete", Value: openAPIOperation(structName, "Delete"+structName, "Delete a record from "+tableInfo.TableName, keyParams, nil,
+				yaml.MapSlice{{Key: "200", Value: rowsAffected}, badRequest, notFound, conflict, serverError})},
+		}},
+	}
+
+	if tableInfo.SoftDeleteField != nil {
+		paths = append(paths,
+			yaml.MapItem{Key: item + "/restore", Value: yaml.MapSlice{
+				{Key: "post", Value: openAPIOperation(structName, "Restore"+structName, "Restore a soft deleted record in "+tableInfo.TableName, keyParams, nil,
+					yaml.MapSlice{{Key: "200", Value: rowsAffected}, badRequest, notFound, conflict, serverError})},
+			}},
+			yaml.MapItem{Key: item + "/hard", Value: yaml.MapSlice{
+				{Key: "delete", Value: openAPIOperation(structName, "HardDelete"+structName, "Permanently delete a record from "+tableInfo.TableName, keyParams, nil,
+					yaml.MapSlice{{Key: "200", Value: rowsAffected}, badRequest, notFound, conflict, serverError})},
+			}},
+		)
+	}
+
+	return paths
+}
+
+// openAPIStreamPath path of the handler streaming the records of a table as newline delimited json
+func openAPIStreamPath(tableInfo *ModelInfo, router *RouterInfo) yaml.MapItem {
+	structName := tableInfo.StructName
+	collection := strings.ToLower(inflection.Plural(structName))
+
+	params := []yaml.MapSlice{openAPIQueryParam("order", "string", "db sort order columns separated by commas, each optionally followed by asc or desc")}
+	if tableInfo.SoftDeleteField != nil {
+		params = append(params, openAPIQueryParam("include_deleted", "boolean", "include soft deleted records"))
+	}
+
+	streamed := yaml.MapSlice{
+		{Key: "description", Value: "OK, one json record per line, query params named after a field filter the records on the values of the field"},
+		{Key: "content", Value: yaml.MapSlice{
+			{Key: "application/x-ndjson", Value: yaml.MapSlice{{Key: "schema", Value: yaml.MapSlice{{Key: "$ref", Value: "#/components/schemas/" + structName}}}}},
+		}},
+	}
+
+	return yaml.MapItem{Key: "/" + fmt.Sprintf(router.CollectionAction, collection, "stream"), Value: yaml.MapSlice{
+		{Key: "get", Value: openAPIOperation(structName, "Stream"+inflection.Plural(structName), "Stream the records of "+tableInfo.TableName, params, nil,
+			yaml.MapSlice{{Key: "200", Value: streamed}, {Key: "400", Value: openAPIError("order is not a list of columns")},
+				{Key: "422", Value: openAPIError("a filter value cannot be parsed as the type of its field")}, {Key: "500", Value: openAPIError("internal server error")}})},
+	}}
+}
+
+// openAPIImportExportPaths paths of the export and import of the records of a table, the route of the actions depends on the router
+func openAPIImportExportPaths(tableInfo *ModelInfo, router *RouterInfo) yaml.MapSlice {
+	structName := tableInfo.StructName
+	collection := strings.ToLower(inflection.Plural(structName))
+	records := yaml.MapSlice{{Key: "type", Value: "array"}, {Key: "items", Value: yaml.MapSlice{{Key: "$ref", Value: "#/components/schemas/" + structName}}}}
+	text := yaml.MapSlice{{Key: "schema", Value: yaml.MapSlice{{Key: "type", Value: "string"}}}}
+
+	exportParams := []yaml.MapSlice{
+		openAPIQueryParam("format", "string", "export format [csv | json | ndjson] (defaults to json)"),
+		openAPIQueryParam("order", "string", "db sort order column"),
+	}
+	if tableInfo.SoftDeleteField != nil {
+		exportParams = append(exportParams, openAPIQueryParam("include_deleted", "boolean", "include soft deleted records"))
+	}
+
+	exported := yaml.MapSlice{
+		{Key: "description", Value: "OK"},
+		{Key: "content", Value: yaml.MapSlice{
+			{Key: "application/json", Value: yaml.MapSlice{{Key: "schema", Value: records}}},
+			{Key: "application/x-ndjson", Value: text},
+			{Key: "text/csv", Value: text},
+		}},
+	}
+	body := yaml.MapSlice{
+		{Key: "required", Value: true},
+		{Key: "content", Value: yaml.MapSlice{
+			{Key: "application/json", Value: yaml.MapSlice{{Key: "schema", Value: records}}},
+			{Key: "application/x-ndjson", Value: text},
+			{Key: "text/csv", Value: text},
+		}},
+	}
+	imported := openAPIResponse("Created", yaml.MapSlice{{Key: "$ref", Value: "#/components/schemas/ImportResult"}})
+
+	return yaml.MapSlice{
+		{Key: "/" + fmt.Sprintf(router.CollectionAction, collection, "export"), Value: yaml.MapSlice{
+			{Key: "get", Value: openAPIOperation(structName, "Export"+inflection.Plural(structName), "Export the records of "+tableInfo.TableName, exportParams, nil,
+				yaml.MapSlice{{Key: "200", Value: exported}, {Key: "400", Value: openAPIError("bad request")}, {Key: "500", Value: openAPIError("internal server error")}})},
+		}},
+		{Key: "/" + fmt.Sprintf(router.CollectionAction, collection, "import"), Value: yaml.MapSlice{
+			{Key: "post", Value: openAPIOperation(structName, "Import"+inflection.Plural(structName), "Import records into "+tableInfo.TableName,
+				[]yaml.MapSlice{
+					openAPIQueryParam("format", "string", "import format [csv | json | ndjson] (defaults to the content type, json)"),
+					openAPIQueryParam("keep_keys", "boolean", "insert the auto increment keys of the records, so the records keep their relations"),
+				}, body,
+				yaml.MapSlice{
+					{Key: "201", Value: imported},
+					{Key: "400", Value: openAPIError("bad request")},
+					{Key: "409", Value: openAPIError("a unique or foreign key constraint was violated, no record was added")},
+					{Key: "422", Value: openAPIError("validation failed, errors lists the fields prefixed with the index of the record")},
+					{Key: "500", Value: openAPIError("internal server error")},
+				})},
+		}},
+	}
+}
+
+func openAPIOperation(tag, operationID, summary string, params []yaml.MapSlice, body yaml.MapSlice, responses yaml.MapSlice) yaml.MapSlice {
+	op := yaml.MapSlice{
+		{Key: "tags", Value: []string{tag}},
+		{Key: "summary", Value: summary},
+		{Key: "operationId", Value: operationID},
+	}
+	if len(params) > 0 {
+		op = append(op, yaml.MapItem{Key: "parameters", Value: params})
+	}
+	if body != nil {
+		op = append(op, yaml.MapItem{Key: "requestBody", Value: body})
+	}
+	return append(op, yaml.MapItem{Key: "responses", Value: responses})
+}
+
+func openAPIQueryParam(name, typ, description string) yaml.MapSlice {
+	return yaml.MapSlice{
+		{Key: "name", Value: name},
+		{Key: "in", Value: "query"},
+		{Key: "description", Value: description},
+		{Key: "schema", Value: yaml.MapSlice{{Key: "type", Value: typ}}},
+	}
+}
+
+func openAPIResponse(description string, schema yaml.MapSlice) yaml.MapSlice {
+	return yaml.MapSlice{
+		{Key: "description", Value: description},
+		{Key: "content", Value: yaml.MapSlice{{Key: "application/json", Value: yaml.MapSlice{{Key: "schema", Value: schema}}}}},
+	}
+}
+
+// openAPIError problem details response, errors are sent as application/problem+json
+func openAPIError(description string) yaml.MapSlice {
+	return yaml.MapSlice{
+		{Key: "description", Value: description},
+		{Key: "content", Value: yaml.MapSlice{{Key: "application/problem+json", Value: yaml.MapSlice{
+			{Key: "schema", Value: yaml.MapSlice{{Key: "$ref", Value: "#/components/schemas/HTTPError"}}},
+		}}}},
+	}
+}
+
+// openAPIModelSchema object schema for a table, columns maintained by the db or the dao are read only
+func openAPIModelSchema(tableInfo *ModelInfo) yaml.MapSlice {
+	properties := yaml.MapSlice{}
+	var required []string
+	for _, f := range tableInfo.CodeFields {
+		schema := openAPIFieldSchema(f)
+
+		readOnly := f.ColumnMeta.IsAutoIncrement() || f == tableInfo.CreatedAtField || f == tableInfo.UpdatedAtField || f == tableInfo.SoftDeleteField
+		if readOnly {
+			schema = append(schema, yaml.MapItem{Key: "readOnly", Value: true})
+		} else if !f.ColumnMeta.Nullable() && f.ColumnMeta.DefaultValue() == "" {
+			required = append(required, f.JSONFieldName)
+		}
+
+		properties = append(properties, yaml.MapItem{Key: f.JSONFieldName, Value: schema})
+	}
+
+	schema := yaml.MapSlice{{Key: "type", Value: "object"}}
+	if len(required) > 0 {
+		schema = append(schema, yaml.MapItem{Key: "required", Value: required})
+	}
+	return append(schema, yaml.MapItem{Key: "properties", Value: properties})
+}
+
+// openAPIType type and format of a column from its swagger type
+func openAPIType(f *FieldInfo) (typ, format string) {
+	switch f.SqlMapping.SwaggerType {
+	case "bool":
+		typ = "boolean"
+	case "int":
+		typ = "integer"
+	case "int64":
+		typ, format = "integer", "int64"
+	case "float32":
+		typ, format = "number", "float"
+	case "float64":
+		typ, format = "number", "double"
+	case "time.Time":
+		typ, format = "string", "date-time"
+	case "[]byte":
+		typ, format = "string", "byte"
+	default:
+		typ = "string"
+		if f.SqlMapping.GoType == "uuid.UUID" {
+			format = "uuid"
+		}
+	}
+	return typ, format
+}
+
+// openAPIFieldSchema schema for a column from its swagger type, nullability, length, range, enum values and default
+func openAPIFieldSchema(f *FieldInfo) yaml.MapSlice {
+	typ, format := openAPIType(f)
+	schema := yaml.MapSlice{{Key: "type", Value: typ}}
+	if format != "" {
+		schema = append(schema, yaml.MapItem{Key: "format", Value: format})
+	}
+
+	if f.ColumnMeta.Nullable() {
+		schema = append(schema, yaml.MapItem{Key: "nullable", Value: true})
+	}
+
+	if typ == "string" && format == "" && f.ColumnMeta.ColumnLength() > 0 {
+		schema = append(schema, yaml.MapItem{Key: "maxLength", Value: f.ColumnMeta.ColumnLength()})
+	}
+
+	if v := f.Validation; v != nil {
+		if v.HasRange && typ == "integer" {
+			schema = append(schema, yaml.MapItem{Key: "minimum", Value: v.Min}, yaml.MapItem{Key: "maximum", Value: v.Max})
+		}
+
+		if len(v.Enum) > 0 && typ == "string" {
+			schema = append(schema, yaml.MapItem{Key: "enum", Value: v.Enum})
+		}
+	}
+
+	if value, ok := openAPIDefault(typ, format, f.ColumnMeta.DefaultValue()); ok {
+		schema = append(schema, yaml.MapItem{Key: "default", Value: value})
+	}
+
+	return schema
+}
+
+// openAPIDefault converts a column default to a value of the schema type, db expressions such as CURRENT_TIMESTAMP are skipped
+func openAPIDefault(typ, format, defaultValue string) (interface{}, bool) {
+	defaultValue = strings.TrimSpace(defaultValue)
+	for len(defaultValue) > 1 && defaultValue[0] == '(' && defaultValue[len(defaultValue)-1] == ')' {
+		defaultValue = strings.TrimSpace(defaultValue[1 : len(defaultValue)-1])
+	}
+
+	if defaultValue == "" || strings.EqualFold(defaultValue, "null") {
+		return nil, false
+	}
+
+	switch typ {
+	case "boolean":
+		switch strings.ToLower(strings.Trim(defaultValue, "'")) {
+		case "1", "true", "b'1'":
+			return true, true
+		case "0", "false", "b'0'":
+			return false, true
+		}
+	case "integer":
+		if v, err := strconv.ParseInt(strings.Trim(defaultValue, "'"), 10, 64); err == nil {
+			return v, true
+		}
+	case "number":
+		if v, err := strconv.ParseFloat(strings.Trim(defaultValue, "'"), 64); err == nil {
+			return v, true
+		}
+	case "string":
+		if format == "" && len(defaultValue) > 1 && defaultValue[0] == '\'' && defaultValue[len(defaultValue)-1] == '\'' {
+			return strings.Replace(defaultValue[1:len(defaultValue)-1], "''", "'", -1), true
+		}
+	}
+	return nil, false
+}
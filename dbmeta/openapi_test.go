@@ -0,0 +1,30 @@
+package dbmeta
+
+import (
+	"testing"
+)
+
+func Test_openAPIDefault(t *testing.T) {
+	tests := []struct {
+		typ, format, defaultValue string
+		expected                  interface{}
+		ok                        bool
+	}{
+		{"integer", "", "0", int64(0), true},
+		{"integer", "", "('42')", int64(42), true},
+		{"number", "double", "1.5", 1.5, true},
+		{"boolean", "", "1", true, true},
+		{"boolean", "", "false", false, true},
+		{"string", "", "'it''s'", "it's", true},
+		{"string", "", "NULL", nil, false},
+		{"string", "date-time", "CURRENT_TIMESTAMP", nil, false},
+		{"string", "", "gen_random_uuid()", nil, false},
+	}
+
+	for _, tt := range tests {
+		value, ok := openAPIDefault(tt.typ, tt.format, tt.defaultValue)
+		if ok != tt.ok || value != tt.expected {
+			t.Errorf("%s %s: expect: %v %t, but got %v %t", tt.typ, tt.defaultValue, tt.expected, tt.ok, value, ok)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package dbmeta
+
+import (
+	"sort"
+	"strings"
+)
+
+// protobufGoTypes go types protoc-gen-go generates for the protobuf scalar and well known types
+var protobufGoTypes = map[string]string{
+	"double":   "float64",
+	"float":    "float32",
+	"int32":    "int32",
+	"int64":    "int64",
+	"uint32":   "uint32",
+	"uint64":   "uint64",
+	"sint32":   "int32",
+	"sint64":   "int64",
+	"fixed32":  "uint32",
+	"fixed64":  "uint64",
+	"sfixed32": "int32",
+	"sfixed64": "int64",
+	"bool":     "bool",
+	"string":   "string",
+	"bytes":    "[]byte",
+
+	"google.protobuf.Timestamp":   "*timestamppb.Timestamp",
+	"google.protobuf.DoubleValue": "*wrapperspb.DoubleValue",
+	"google.protobuf.FloatValue":  "*wrapperspb.FloatValue",
+	"google.protobuf.Int64Value":  "*wrapperspb.Int64Value",
+	"google.protobuf.UInt64Value": "*wrapperspb.UInt64Value",
+	"google.protobuf.Int32Value":  "*wrapperspb.Int32Value",
+	"google.protobuf.UInt32Value": "*wrapperspb.UInt32Value",
+	"google.protobuf.BoolValue":   "*wrapperspb.BoolValue",
+	"google.protobuf.StringValue": "*wrapperspb.StringValue",
+	"google.protobuf.BytesValue":  "*wrapperspb.BytesValue",
+}
+
+// protobufWrapperTypes wrapper types used for the scalar types of nullable columns, proto3 scalars cannot hold NULL
+var protobufWrapperTypes = map[string]string{
+	"double":   "google.protobuf.DoubleValue",
+	"float":    "google.protobuf.FloatValue",
+	"int64":    "google.protobuf.Int64Value",
+	"sint64":   "google.protobuf.Int64Value",
+	"sfixed64": "google.protobuf.Int64Value",
+	"uint64":   "google.protobuf.UInt64Value",
+	"fixed64":  "google.protobuf.UInt64Value",
+	"int32":    "google.protobuf.Int32Value",
+	"sint32":   "google.protobuf.Int32Value",
+	"sfixed32": "google.protobuf.Int32Value",
+	"uint32":   "google.protobuf.UInt32Value",
+	"fixed32":  "google.protobuf.UInt32Value",
+	"bool":     "google.protobuf.BoolValue",
+	"string":   "google.protobuf.StringValue",
+	"bytes":    "google.protobuf.BytesValue",
+}
+
+// protobufFieldType protobuf type of a column, nullable scalars use the wrapper types, message types such as
+// google.protobuf.Timestamp can already be unset
+func protobufFieldType(protobufType string, nullable bool) string {
+	if !nullable {
+		return protobufType
+	}
+
+	if wrapper, ok := protobufWrapperTypes[protobufType]; ok {
+		return wrapper
+	}
+	return protobufType
+}
+
+// protobufValueType go type of the value held by a protobuf field, wrappers hold a scalar and timestamps a time.Time
+func protobufValueType(protobufType string) (string, bool) {
+	if protobufType == "google.protobuf.Timestamp" {
+		return "time.Time", true
+	}
+
+	for scalar, wrapper := range protobufWrapperTypes {
+		if wrapper == protobufType {
+			return protobufGoTypes[scalar], true
+		}
+	}
+
+	goType, ok := protobufGoTypes[protobufType]
+	return goType, ok
+}
+
+// ProtobufImports well known type proto files imported by the messages of the tables
+func ProtobufImports(tableInfos map[string]*ModelInfo) []string {
+	imports := make(map[string]bool)
+	for _, tableInfo := range tableInfos {
+		for _, f := range tableInfo.CodeFields {
+			switch {
+			case f.ProtobufType == "google.protobuf.Timestamp":
+				imports["google/protobuf/timestamp.proto"] = true
+			case strings.HasPrefix(f.ProtobufType, "google.protobuf.") && strings.HasSuffix(f.ProtobufType, "Value"):
+				imports["google/protobuf/wrappers.proto"] = true
+			}
+		}
+	}
+
+	var files []string
+	for file := range imports {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	return files
+}
@@ -0,0 +1,136 @@
+package dbmeta
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+)
+
+// ProtobufLock field numbers assigned to the columns of each table, persisted across regenerations so that reordering or
+// dropping a column does not renumber the fields of the protobuf messages
+type ProtobufLock struct {
+	Messages map[string]*ProtobufMessageLock `json:"messages"`
+}
+
+// ProtobufMessageLock field numbers of the columns of a table, numbers and names of dropped columns are reserved
+type ProtobufMessageLock struct {
+	Fields        map[string]int `json:"fields"`
+	Reserved      []int          `json:"reserved,omitempty"`
+	ReservedNames []string       `json:"reserved_names,omitempty"`
+}
+
+// LoadProtobufLock reads the lock file, a missing file is an empty lock
+func LoadProtobufLock(filename string) (*ProtobufLock, error) {
+	lock := &ProtobufLock{Messages: make(map[string]*ProtobufMessageLock)}
+
+	b, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return lock, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal(b, lock); err != nil {
+		return nil, fmt.Errorf("unable to parse protobuf lock file %s error: %v", filename, err)
+	}
+
+	if lock.Messages == nil {
+		lock.Messages = make(map[string]*ProtobufMessageLock)
+	}
+	return lock, nil
+}
+
+// Save writes the lock file
+func (l *ProtobufLock) Save(filename string) error {
+	b, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, append(b, '\n'), 0666)
+}
+
+// LockFields returns the field number of each column of the table. Columns keep the number they were assigned, new columns
+// are numbered after the highest number used or reserved and columns no longer in the table are reserved.
+func (l *ProtobufLock) LockFields(tableName, nameFormat string, columns []string) map[string]int {
+	msg, ok := l.Messages[tableName]
+	if !ok {
+		msg = &ProtobufMessageLock{Fields: make(map[string]int)}
+		l.Messages[tableName] = msg
+	}
+	if msg.Fields == nil {
+		msg.Fields = make(map[string]int)
+	}
+
+	current := make(map[string]bool)
+	for _, name := range columns {
+		current[name] = true
+	}
+
+	dropped := make([]string, 0)
+	for name := range msg.Fields {
+		if !current[name] {
+			dropped = append(dropped, name)
+		}
+	}
+	sort.Strings(dropped)
+
+	for _, name := range dropped {
+		msg.Reserved = append(msg.Reserved, msg.Fields[name])
+		msg.ReservedNames = append(msg.ReservedNames, formatName(nameFormat, name))
+		delete(msg.Fields, name)
+	}
+	sort.Ints(msg.Reserved)
+
+	// a column added back with the name of a dropped column is given a new number, only the old number stays reserved
+	var reservedNames []string
+	for _, name := range msg.ReservedNames {
+		if !containsFormattedName(columns, nameFormat, name) {
+			reservedNames = append(reservedNames, name)
+		}
+	}
+	msg.ReservedNames = reservedNames
+
+	next := 1
+	for _, n := range msg.Fields {
+		if n >= next {
+			next = n + 1
+		}
+	}
+	for _, n := range msg.Reserved {
+		if n >= next {
+			next = n + 1
+		}
+	}
+
+	numbers := make(map[string]int)
+	for _, name := range columns {
+		n, ok := msg.Fields[name]
+		if !ok {
+			n = next
+			next++
+			msg.Fields[name] = n
+		}
+		numbers[name] = n
+	}
+	return numbers
+}
+
+func containsFormattedName(columns []string, nameFormat, name string) bool {
+	for _, column := range columns {
+		if formatName(nameFormat, column) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Message returns the lock of the table, nil if the table is not locked
+func (l *ProtobufLock) Message(tableName string) *ProtobufMessageLock {
+	if l == nil {
+		return nil
+	}
+	return l.Messages[tableName]
+}
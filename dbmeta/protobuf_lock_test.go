@@ -0,0 +1,79 @@
+package dbmeta
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestProtobufLock_LockFields(t *testing.T) {
+	lock := &ProtobufLock{Messages: make(map[string]*ProtobufMessageLock)}
+
+	numbers := lock.LockFields("users", "snake", []string{"id", "name", "Email"})
+	expected := map[string]int{"id": 1, "name": 2, "Email": 3}
+	if !reflect.DeepEqual(numbers, expected) {
+		t.Fatalf("expect: %v, but got %v", expected, numbers)
+	}
+
+	// reordered, dropped name and added phone
+	numbers = lock.LockFields("users", "snake", []string{"Email", "id", "phone"})
+	expected = map[string]int{"id": 1, "Email": 3, "phone": 4}
+	if !reflect.DeepEqual(numbers, expected) {
+		t.Fatalf("expect: %v, but got %v", expected, numbers)
+	}
+
+	msg := lock.Message("users")
+	if !reflect.DeepEqual(msg.Reserved, []int{2}) || !reflect.DeepEqual(msg.ReservedNames, []string{"name"}) {
+		t.Fatalf("expect reserved: [2] [name], but got %v %v", msg.Reserved, msg.ReservedNames)
+	}
+
+	// name added back gets a new number and is no longer a reserved name
+	numbers = lock.LockFields("users", "snake", []string{"id", "name", "Email", "phone"})
+	if numbers["name"] != 5 {
+		t.Fatalf("expect: name = 5, but got %d", numbers["name"])
+	}
+	if !reflect.DeepEqual(msg.Reserved, []int{2}) || len(msg.ReservedNames) != 0 {
+		t.Fatalf("expect reserved: [2] [], but got %v %v", msg.Reserved, msg.ReservedNames)
+	}
+
+	dir, err := ioutil.TempDir("", "protobuf_lock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "test.proto.lock")
+	if err := lock.Save(filename); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadProtobufLock(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded.Message("users").Fields, msg.Fields) {
+		t.Fatalf("expect: %v, but got %v", msg.Fields, loaded.Message("users").Fields)
+	}
+}
+
+func Test_protobufFieldType(t *testing.T) {
+	tests := []struct {
+		protobufType string
+		nullable     bool
+		expected     string
+	}{
+		{"int32", false, "int32"},
+		{"int32", true, "google.protobuf.Int32Value"},
+		{"string", true, "google.protobuf.StringValue"},
+		{"google.protobuf.Timestamp", true, "google.protobuf.Timestamp"},
+	}
+
+	for _, tt := range tests {
+		protobufType := protobufFieldType(tt.protobufType, tt.nullable)
+		if protobufType != tt.expected {
+			t.Errorf("%s nullable: %t expect: %s, but got %s", tt.protobufType, tt.nullable, tt.expected, protobufType)
+		}
+	}
+}
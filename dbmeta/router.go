@@ -0,0 +1,87 @@
+package dbmeta
+
+import (
+	"fmt"
+	"sort"
+)
+
+// RouterInfo describes how the generated api handlers are declared and read path parameters for a --router backend
+type RouterInfo struct {
+	Name string
+	// HandlerParams parameter list of a handler func
+	HandlerParams string
+	// HandlerResult result of a handler func, echo handlers return an error
+	HandlerResult string
+	// HandlerPreamble statement declaring w and r when the handler is passed a framework context
+	HandlerPreamble string
+	// HandlerReturn appended to the return statements of a handler, echo handlers return nil once the response is written
+	HandlerReturn string
+	// PathParam printf format of the expression reading a path parameter by name
+	PathParam string
+	// PathSegment printf format of a path parameter in a route
+	PathSegment string
+	// CollectionAction printf format of the route of an action on a collection, passed the collection and the action. The
+	// gin and httprouter trees cannot hold a static segment next to the key of the records, their actions prefix the collection.
+	CollectionAction string
+}
+
+var routers = map[string]*RouterInfo{
+	"httprouter": {
+		Name:             "httprouter",
+		HandlerParams:    "w http.ResponseWriter, r *http.Request, ps httprouter.Params",
+		PathParam:        "ps.ByName(%q)",
+		PathSegment:      ":%s",
+		CollectionAction: "%[2]s/%[1]s",
+	},
+	"gin": {
+		Name:             "gin",
+		HandlerParams:    "c *gin.Context",
+		HandlerPreamble:  "w, r := c.Writer, c.Request",
+		PathParam:        "c.Param(%q)",
+		PathSegment:      ":%s",
+		CollectionAction: "%[2]s/%[1]s",
+	},
+	"nethttp": {
+		Name:             "nethttp",
+		HandlerParams:    "w http.ResponseWriter, r *http.Request",
+		PathParam:        "r.PathValue(%q)",
+		PathSegment:      "{%s}",
+		CollectionAction: "%[1]s/%[2]s",
+	},
+	"chi": {
+		Name:             "chi",
+		HandlerParams:    "w http.ResponseWriter, r *http.Request",
+		PathParam:        "chi.URLParam(r, %q)",
+		PathSegment:      "{%s}",
+		CollectionAction: "%[1]s/%[2]s",
+	},
+	"echo": {
+		Name:             "echo",
+		HandlerParams:    "c echo.Context",
+		HandlerResult:    "error",
+		HandlerPreamble:  "w, r := c.Response(), c.Request()",
+		HandlerReturn:    " nil",
+		PathParam:        "c.Param(%q)",
+		PathSegment:      ":%s",
+		CollectionAction: "%[1]s/%[2]s",
+	},
+}
+
+// RouterNames names of the supported --router backends
+func RouterNames() []string {
+	names := make([]string, 0, len(routers))
+	for name := range routers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LookupRouter returns the RouterInfo for a --router backend
+func LookupRouter(name string) (*RouterInfo, error) {
+	router, ok := routers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown router: %s, supported routers: %v", name, RouterNames())
+	}
+	return router, nil
+}
@@ -0,0 +1,528 @@
+package dbmeta
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bxcodec/faker/v3"
+	"gopkg.in/yaml.v2"
+)
+
+// SeedTable fake rows of a table, the values of each row are in the order of the columns
+type SeedTable struct {
+	Name    string
+	Columns []string
+	Rows    [][]interface{}
+}
+
+// Seeder generates fake rows of tables. Values of foreign key columns are picked from the keys of the referenced table, so
+// the tables are generated in SeedOrder. Values of auto increment columns are only generated with AutoIncrement set, the
+// rows written to fixtures and sql files need them for the foreign keys referencing the table.
+type Seeder struct {
+	Rows          int
+	AutoIncrement bool
+	keys          map[string][]interface{}
+	unique        map[string]map[string]bool
+}
+
+// NewSeeder seeder generating rows rows of each table
+func NewSeeder(rows int, autoIncrement bool) *Seeder {
+	return &Seeder{Rows: rows, AutoIncrement: autoIncrement, keys: make(map[string][]interface{}), unique: make(map[string]map[string]bool)}
+}
+
+// SetKeys sets the values foreign keys referencing the column of the table are picked from
+func (sd *Seeder) SetKeys(table, column string, values []interface{}) {
+	sd.keys[table+"."+column] = values
+}
+
+// SeedOrder the tables of the snapshot ordered so each table follows the tables its foreign keys reference. Tables in a
+// reference cycle follow the other tables, their foreign keys referencing tables seeded later are null.
+func SeedOrder(s *SchemaSnapshot) []*TableSchema {
+	var order []*TableSchema
+	seeded := make(map[string]bool)
+
+	for len(order) < len(s.Tables) {
+		added := false
+		for _, t := range s.Tables {
+			if seeded[t.Name] || !seedReady(s, t, seeded) {
+				continue
+			}
+			order = append(order, t)
+			seeded[t.Name] = true
+			added = true
+		}
+
+		if !added {
+			for _, t := range s.Tables {
+				if !seeded[t.Name] {
+					order = append(order, t)
+					seeded[t.Name] = true
+					break
+				}
+			}
+		}
+	}
+	return order
+}
+
+// seedReady reports whether the tables referenced by the foreign keys of the table are seeded
+func seedReady(s *SchemaSnapshot, t *TableSchema, seeded map[string]bool) bool {
+	for _, fk := range t.ForeignKeys {
+		if fk.RefTable != t.Name && !seeded[fk.RefTable] && s.Table(fk.RefTable) != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// GenerateTable fake rows of the table. Strings fit the column length, enums use the allowed values, integers fit the range of
+// the column type and the values of the primary key and unique indexes are unique within the rows generated.
+func (sd *Seeder) GenerateTable(t *TableSchema, info *ModelInfo) (*SeedTable, error) {
+	table := &SeedTable{Name: t.Name}
+
+	var fields []*FieldInfo
+	for _, name := range sd.seedColumns(t) {
+		f := seedField(info, name)
+		if f == nil {
+			continue
+		}
+		table.Columns = append(table.Columns, name)
+		fields = append(fields, f)
+	}
+
+	uniqueKeys := seedUniqueKeys(t, table.Columns)
+
+	for n := 0; n < sd.Rows; n++ {
+		var row []interface{}
+		var err error
+
+		// a row repeating the values of a unique key is generated again
+		for attempt := 0; ; attempt++ {
+			row, err = sd.generateRow(t, info.DBMeta.SQLType(), table.Columns, fields, n)
+			if err != nil {
+				return nil, err
+			}
+			if sd.seedUnique(t.Name, table.Columns, row, uniqueKeys) {
+				break
+			}
+			if attempt == 100 {
+				return nil, fmt.Errorf("unable to generate %d rows of %s with unique values", sd.Rows, t.Name)
+			}
+		}
+		table.Rows = append(table.Rows, row)
+	}
+
+	for i, column := range table.Columns {
+		var values []interface{}
+		for _, row := range table.Rows {
+			if row[i] != nil {
+				values = append(values, row[i])
+			}
+		}
+		sd.SetKeys(t.Name, column, values)
+	}
+	return table, nil
+}
+
+// LoadKeys loads the values of the columns referenced by the foreign keys of the table and the values of the unique keys of
+// the table from the database, so rows are generated referencing the rows already in the referenced tables and not repeating
+// the unique values of the rows already in the table
+func (sd *Seeder) LoadKeys(db *sql.DB, sqlType string, t *TableSchema) error {
+	d := migrationDialect(migrationSQLType(sqlType))
+
+	for _, fk := range t.ForeignKeys {
+		keySQL := fmt.Sprintf("SELECT DISTINCT %s FROM %s WHERE %s IS NOT NULL", d.quote(fk.RefColumn), d.quote(fk.RefTable), d.quote(fk.RefColumn))
+		res, err := db.Query(keySQL)
+		if err != nil {
+			return fmt.Errorf("unable to load keys of %s.%s: %v", fk.RefTable, fk.RefColumn, err)
+		}
+
+		var values []interface{}
+		for res.Next() {
+			var value interface{}
+			err = res.Scan(&value)
+			if err != nil {
+				res.Close()
+				return fmt.Errorf("unable to load keys of %s.%s Scan: %v", fk.RefTable, fk.RefColumn, err)
+			}
+			values = append(values, value)
+		}
+		res.Close()
+		sd.SetKeys(fk.RefTable, fk.RefColumn, values)
+	}
+
+	for _, key := range seedUniqueKeys(t, sd.seedColumns(t)) {
+		uniqueSQL := fmt.Sprintf("SELECT %s FROM %s", d.quoteList(key), d.quote(t.Name))
+		res, err := db.Query(uniqueSQL)
+		if err != nil {
+			return fmt.Errorf("unable to load unique values of %s: %v", t.Name, err)
+		}
+
+		values := make([]interface{}, len(key))
+		pointers := make([]interface{}, len(key))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+
+		name := seedUniqueName(t.Name, key)
+		sd.unique[name] = make(map[string]bool)
+		for res.Next() {
+			err = res.Scan(pointers...)
+			if err != nil {
+				res.Close()
+				return fmt.Errorf("unable to load unique values of %s Scan: %v", t.Name, err)
+			}
+
+			var parts []string
+			for _, value := range values {
+				parts = append(parts, seedKeyString(value))
+			}
+			sd.unique[name][strings.Join(parts, "\x00")] = true
+		}
+		res.Close()
+	}
+	return nil
+}
+
+func (sd *Seeder) generateRow(t *TableSchema, sqlType string, columns []string, fields []*FieldInfo, n int) ([]interface{}, error) {
+	row := make([]interface{}, len(columns))
+
+	for i, name := range columns {
+		c := t.column(name)
+		f := fields[i]
+
+		if fk := seedForeignKey(t, name); fk != nil {
+			keys := sd.keys[fk.RefTable+"."+fk.RefColumn]
+			switch {
+			case len(keys) > 0:
+				row[i] = keys[rand.Intn(len(keys))]
+			case !c.Nullable:
+				return nil, fmt.Errorf("unable to seed %s.%s, there are no rows in %s to reference", t.Name, name, fk.RefTable)
+			}
+			continue
+		}
+
+		if c.AutoIncrement {
+			row[i] = int64(n + 1)
+			continue
+		}
+
+		// one in ten nullable columns is left null
+		if c.Nullable && rand.Intn(10) == 0 {
+			continue
+		}
+		row[i] = seedValue(f, sqlType)
+	}
+	return row, nil
+}
+
+// seedValue fake value of a field fitted to the column length, enum values and integer range of the column
+func seedValue(f *FieldInfo, sqlType string) interface{} {
+	v := createFieldValidation(sqlType, f.ColumnMeta, f.GoFieldType)
+
+	goType := goBaseType(f.GoFieldType)
+	if f.SqlMapping != nil {
+		goType = f.SqlMapping.GoType
+	}
+
+	if v != nil && len(v.Enum) > 0 {
+		return v.Enum[rand.Intn(len(v.Enum))]
+	}
+	if value, ok := fakeRuleValue(f); ok {
+		return value
+	}
+
+	switch goType {
+	case "string":
+		s := faker.Sentence()
+		if f.ColumnMeta.ColumnLength() > 0 && f.ColumnMeta.ColumnLength() < 40 {
+			s = faker.Word() + " " + randomLetters(6)
+		}
+		return fitLength(s, f.ColumnMeta.ColumnLength())
+
+	case "int", "int32", "int64":
+		min, max := int64(1), int64(100000)
+		if v != nil && v.HasRange {
+			if v.Min > min {
+				min = v.Min
+			}
+			if v.Max < max {
+				max = v.Max
+			}
+		}
+		return min + rand.Int63n(max-min+1)
+
+	case "float32", "float64":
+		return float64(rand.Intn(100000)) / 100
+
+	case "bool":
+		return rand.Intn(2) == 1
+
+	case "time.Time":
+		return sampleTime.Add(time.Duration(rand.Int63n(int64(365 * 24 * time.Hour)))).Truncate(time.Second)
+
+	case "[]byte":
+		b := make([]byte, 16)
+		rand.Read(b)
+		return b
+	}
+	return fitLength(faker.Word(), f.ColumnMeta.ColumnLength())
+}
+
+// fitLength truncates the string to the column length, a length of 0 is unlimited
+func fitLength(s string, length int64) string {
+	if length > 0 && int64(len([]rune(s))) > length {
+		return strings.TrimSpace(string([]rune(s)[:length]))
+	}
+	return s
+}
+
+func randomLetters(n int) string {
+	const letters = "abcdefghijklmnopqrstuvwxyz"
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = letters[rand.Intn(len(letters))]
+	}
+	return string(b)
+}
+
+func seedField(info *ModelInfo, column string) *FieldInfo {
+	for _, f := range info.CodeFields {
+		if f.ColumnMeta.Name() == column {
+			return f
+		}
+	}
+	return nil
+}
+
+func seedForeignKey(t *TableSchema, column string) *ForeignKeySchema {
+	for _, fk := range t.ForeignKeys {
+		if fk.Column == column {
+			return fk
+		}
+	}
+	return nil
+}
+
+// seedColumns columns of the table the rows have values for, auto increment columns are left to the database unless
+// AutoIncrement is set
+func (sd *Seeder) seedColumns(t *TableSchema) []string {
+	var columns []string
+	for _, c := range t.Columns {
+		if !c.AutoIncrement || sd.AutoIncrement {
+			columns = append(columns, c.Name)
+		}
+	}
+	return columns
+}
+
+// seedUniqueKeys the primary key and the unique indexes of the table, keys with a column that is not generated are left out
+func seedUniqueKeys(t *TableSchema, columns []string) [][]string {
+	var keys [][]string
+	if len(t.PrimaryKey) > 0 {
+		keys = append(keys, t.PrimaryKey)
+	}
+	for _, idx := range t.Indexes {
+		if idx.Unique {
+			keys = append(keys, idx.Columns)
+		}
+	}
+
+	var uniqueKeys [][]string
+	for _, key := range keys {
+		generated := true
+		for _, name := range key {
+			generated = generated && containsString(columns, name)
+		}
+		if generated {
+			uniqueKeys = append(uniqueKeys, key)
+		}
+	}
+	return uniqueKeys
+}
+
+// seedUnique reports whether the row has new values for each unique key of the table and records them, keys with a null
+// value are not compared
+func (sd *Seeder) seedUnique(table string, columns []string, row []interface{}, uniqueKeys [][]string) bool {
+	values := make(map[string]string)
+	for _, key := range uniqueKeys {
+		var parts []string
+		for _, name := range key {
+			for i, column := range columns {
+				if column == name && row[i] != nil {
+					parts = append(parts, seedKeyString(row[i]))
+				}
+			}
+		}
+		if len(parts) != len(key) {
+			continue
+		}
+
+		name := seedUniqueName(table, key)
+		values[name] = strings.Join(parts, "\x00")
+		if sd.unique[name][values[name]] {
+			return false
+		}
+	}
+
+	for name, value := range values {
+		if sd.unique[name] == nil {
+			sd.unique[name] = make(map[string]bool)
+		}
+		sd.unique[name][value] = true
+	}
+	return true
+}
+
+func seedUniqueName(table string, key []string) string {
+	return fmt.Sprintf("%s(%s)", table, strings.Join(key, ","))
+}
+
+// seedKeyString string compared for the value of a unique key, drivers scan text as string or []byte
+func seedKeyString(value interface{}) string {
+	if b, ok := value.([]byte); ok {
+		return string(b)
+	}
+	return fmt.Sprint(value)
+}
+
+// Insert inserts the rows into the table in a transaction
+func (t *SeedTable) Insert(db *sql.DB, sqlType string) error {
+	d := migrationDialect(migrationSQLType(sqlType))
+
+	var placeholders []string
+	for i := range t.Columns {
+		switch d {
+		case "postgres":
+			placeholders = append(placeholders, fmt.Sprintf("$%d", i+1))
+		case "mssql":
+			placeholders = append(placeholders, fmt.Sprintf("@p%d", i+1))
+		default:
+			placeholders = append(placeholders, "?")
+		}
+	}
+	insertSQL := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", d.quote(t.Name), d.quoteList(t.Columns), strings.Join(placeholders, ", "))
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+
+	for _, row := range t.Rows {
+		_, err = tx.Exec(insertSQL, row...)
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("unable to insert into %s: %v", t.Name, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// InsertStatements insert statements of the rows with the values as literals of the dialect
+func (t *SeedTable) InsertStatements(sqlType string) []string {
+	d := migrationDialect(migrationSQLType(sqlType))
+
+	var statements []string
+	for _, row := range t.Rows {
+		var values []string
+		for _, value := range row {
+			values = append(values, d.literal(value))
+		}
+		statements = append(statements, fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s);", d.quote(t.Name), d.quoteList(t.Columns), strings.Join(values, ", ")))
+	}
+	return statements
+}
+
+// literal sql literal of a seed value
+func (d migrationDialect) literal(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "NULL"
+	case string:
+		s := "'" + strings.Replace(v, "'", "''", -1) + "'"
+		if d == "mssql" {
+			return "N" + s
+		}
+		return s
+	case bool:
+		switch {
+		case d == "postgres":
+			return strconv.FormatBool(v)
+		case v:
+			return "1"
+		default:
+			return "0"
+		}
+	case time.Time:
+		return "'" + v.UTC().Format("2006-01-02 15:04:05") + "'"
+	case []byte:
+		switch d {
+		case "postgres":
+			return fmt.Sprintf("'\\x%s'", hex.EncodeToString(v))
+		case "mssql":
+			return "0x" + hex.EncodeToString(v)
+		default:
+			return fmt.Sprintf("X'%s'", hex.EncodeToString(v))
+		}
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
+
+// FixtureJSON rows as a json array of objects keyed by column name, the keys are in column order
+func (t *SeedTable) FixtureJSON() ([]byte, error) {
+	buf := bytes.Buffer{}
+	buf.WriteString("[\n")
+	for i, row := range t.Rows {
+		buf.WriteString("  {")
+		for j, value := range row {
+			if j > 0 {
+				buf.WriteString(", ")
+			}
+			name, err := json.Marshal(t.Columns[j])
+			if err != nil {
+				return nil, err
+			}
+			data, err := json.Marshal(value)
+			if err != nil {
+				return nil, err
+			}
+			buf.Write(name)
+			buf.WriteString(": ")
+			buf.Write(data)
+		}
+		buf.WriteString("}")
+		if i < len(t.Rows)-1 {
+			buf.WriteString(",")
+		}
+		buf.WriteString("\n")
+	}
+	buf.WriteString("]\n")
+	return buf.Bytes(), nil
+}
+
+// FixtureYAML rows as a yaml list of maps keyed by column name, the keys are in column order
+func (t *SeedTable) FixtureYAML() ([]byte, error) {
+	var rows []yaml.MapSlice
+	for _, row := range t.Rows {
+		record := yaml.MapSlice{}
+		for j, value := range row {
+			// bytes are base64 encoded as in the json fixtures
+			if b, ok := value.([]byte); ok {
+				value = base64.StdEncoding.EncodeToString(b)
+			}
+			record = append(record, yaml.MapItem{Key: t.Columns[j], Value: value})
+		}
+		rows = append(rows, record)
+	}
+	return yaml.Marshal(rows)
+}
@@ -0,0 +1,132 @@
+package dbmeta
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func seedTestSchema() (*SchemaSnapshot, map[string]*ModelInfo) {
+	s := &SchemaSnapshot{SQLType: "sqlite3", Tables: []*TableSchema{
+		{
+			Name: "albums",
+			Columns: []*ColumnSchema{
+				{Name: "id", Type: "INTEGER", AutoIncrement: true},
+				{Name: "artist_id", Type: "INTEGER"},
+				{Name: "format", Type: "TEXT"},
+			},
+			PrimaryKey:  []string{"id"},
+			ForeignKeys: []*ForeignKeySchema{{Column: "artist_id", RefTable: "artists", RefColumn: "id"}},
+		},
+		{
+			Name: "artists",
+			Columns: []*ColumnSchema{
+				{Name: "id", Type: "INTEGER", AutoIncrement: true},
+				{Name: "code", Type: "VARCHAR(2)"},
+			},
+			PrimaryKey: []string{"id"},
+			Indexes:    []*IndexSchema{{Name: "uq_code", Columns: []string{"code"}, Unique: true}},
+		},
+	}}
+
+	albums := &testTable{name: "albums", columns: []*testColumn{
+		{name: "id", dbType: "INTEGER", primaryKey: true, autoIncrement: true},
+		{name: "artist_id", dbType: "INTEGER"},
+		{name: "format", dbType: "TEXT", enumValues: []string{"cd", "vinyl"}},
+	}}
+	artists := &testTable{name: "artists", columns: []*testColumn{
+		{name: "id", dbType: "INTEGER", primaryKey: true, autoIncrement: true},
+		{name: "code", dbType: "VARCHAR", length: 2},
+	}}
+
+	infos := make(map[string]*ModelInfo)
+	for _, table := range []*testTable{albums, artists} {
+		info := &ModelInfo{TableName: table.name, DBMeta: table}
+		for _, c := range table.columns {
+			goType := "string"
+			if c.dbType == "INTEGER" {
+				goType = "int64"
+			}
+			info.CodeFields = append(info.CodeFields, &FieldInfo{GoFieldType: goType, ColumnMeta: c})
+		}
+		infos[table.name] = info
+	}
+	return s, infos
+}
+
+func Test_Seeder(t *testing.T) {
+	s, infos := seedTestSchema()
+
+	var names []string
+	order := SeedOrder(s)
+	for _, table := range order {
+		names = append(names, table.Name)
+	}
+	if strings.Join(names, ",") != "artists,albums" {
+		t.Fatalf("seed order: expect: artists,albums, but got %s", strings.Join(names, ","))
+	}
+
+	seeder := NewSeeder(20, true)
+	artists, err := seeder.GenerateTable(order[0], infos["artists"])
+	if err != nil {
+		t.Fatalf("artists: unexpected error: %v", err)
+	}
+	albums, err := seeder.GenerateTable(order[1], infos["albums"])
+	if err != nil {
+		t.Fatalf("albums: unexpected error: %v", err)
+	}
+
+	codes := make(map[interface{}]bool)
+	for i, row := range artists.Rows {
+		if row[0] != int64(i+1) {
+			t.Errorf("artists.id: expect: %d, but got %v", i+1, row[0])
+		}
+		if row[1] != nil && (codes[row[1]] || len(row[1].(string)) > 2) {
+			t.Errorf("artists.code: expect a unique code of at most 2 characters, but got %v", row[1])
+		}
+		codes[row[1]] = true
+	}
+
+	for _, row := range albums.Rows {
+		if id, ok := row[1].(int64); !ok || id < 1 || id > 20 {
+			t.Errorf("albums.artist_id: expect an artist id, but got %v", row[1])
+		}
+		if row[2] != nil && row[2] != "cd" && row[2] != "vinyl" {
+			t.Errorf("albums.format: expect an enum value, but got %v", row[2])
+		}
+	}
+
+	if _, err = NewSeeder(1, true).GenerateTable(order[1], infos["albums"]); err == nil {
+		t.Errorf("albums without artists: expect an error")
+	}
+}
+
+func Test_SeedInsertStatements(t *testing.T) {
+	table := &SeedTable{
+		Name:    "albums",
+		Columns: []string{"id", "title", "active", "cover", "released"},
+		Rows:    [][]interface{}{{int64(1), "it's", true, []byte{0xca, 0xfe}, time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)}},
+	}
+
+	tests := []struct {
+		sqlType  string
+		expected string
+	}{
+		{"postgres", `INSERT INTO "albums" ("id", "title", "active", "cover", "released") VALUES (1, 'it''s', true, '\xcafe', '2020-06-01 12:00:00');`},
+		{"mysql", "INSERT INTO `albums` (`id`, `title`, `active`, `cover`, `released`) VALUES (1, 'it''s', 1, X'cafe', '2020-06-01 12:00:00');"},
+		{"mssql", "INSERT INTO [albums] ([id], [title], [active], [cover], [released]) VALUES (1, N'it''s', 1, 0xcafe, '2020-06-01 12:00:00');"},
+	}
+
+	for _, tt := range tests {
+		statements := table.InsertStatements(tt.sqlType)
+		if len(statements) != 1 || statements[0] != tt.expected {
+			t.Errorf("%s: expect: %s, but got %v", tt.sqlType, tt.expected, statements)
+		}
+	}
+
+	expected := "[\n  {\"id\": 1, \"title\": \"it's\", \"active\": true, \"cover\": \"yv4=\", \"released\": \"2020-06-01T12:00:00Z\"}\n]\n"
+	data, err := table.FixtureJSON()
+	if err != nil || string(data) != expected {
+		t.Errorf("json: expect: %s, but got %s (%v)", expected, data, err)
+	}
+}
@@ -0,0 +1,437 @@
+package dbmeta
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+	"github.com/jinzhu/inflection"
+)
+
+// ddlTypes names of a family of column types in each dialect, %d is replaced with the size of sized types
+var ddlTypes = map[string]map[string]string{
+	"bool":      {"mysql": "tinyint(1)", "postgres": "boolean", "mssql": "bit", "sqlite3": "boolean"},
+	"smallint":  {"mysql": "smallint", "postgres": "smallint", "mssql": "smallint", "sqlite3": "integer"},
+	"int":       {"mysql": "int", "postgres": "integer", "mssql": "int", "sqlite3": "integer"},
+	"bigint":    {"mysql": "bigint", "postgres": "bigint", "mssql": "bigint", "sqlite3": "integer"},
+	"real":      {"mysql": "float", "postgres": "real", "mssql": "real", "sqlite3": "real"},
+	"double":    {"mysql": "double", "postgres": "double precision", "mssql": "float", "sqlite3": "real"},
+	"string":    {"mysql": "varchar(255)", "postgres": "text", "mssql": "nvarchar(max)", "sqlite3": "text"},
+	"varchar":   {"mysql": "varchar(%d)", "postgres": "varchar(%d)", "mssql": "nvarchar(%d)", "sqlite3": "varchar(%d)"},
+	"char":      {"mysql": "char(%d)", "postgres": "char(%d)", "mssql": "nchar(%d)", "sqlite3": "char(%d)"},
+	"text":      {"mysql": "text", "postgres": "text", "mssql": "nvarchar(max)", "sqlite3": "text"},
+	"date":      {"mysql": "date", "postgres": "date", "mssql": "date", "sqlite3": "date"},
+	"time":      {"mysql": "time", "postgres": "time", "mssql": "time", "sqlite3": "time"},
+	"timestamp": {"mysql": "datetime", "postgres": "timestamp", "mssql": "datetime2", "sqlite3": "datetime"},
+	"bytes":     {"mysql": "longblob", "postgres": "bytea", "mssql": "varbinary(max)", "sqlite3": "blob"},
+	"uuid":      {"mysql": "char(36)", "postgres": "uuid", "mssql": "uniqueidentifier", "sqlite3": "text"},
+	"json":      {"mysql": "json", "postgres": "jsonb", "mssql": "nvarchar(max)", "sqlite3": "text"},
+}
+
+// sqlTypeFamilies type families of sql type names, used to carry a type declared in a tag over to another dialect
+var sqlTypeFamilies = map[string]string{
+	"bool": "bool", "boolean": "bool", "bit": "bool",
+	"tinyint": "smallint", "smallint": "smallint", "int2": "smallint",
+	"int": "int", "integer": "int", "int4": "int", "mediumint": "int",
+	"bigint": "bigint", "int8": "bigint",
+	"real": "real", "float4": "real",
+	"double": "double", "double precision": "double", "float8": "double", "float": "double",
+	"varchar": "varchar", "nvarchar": "varchar", "character varying": "varchar", "varchar2": "varchar",
+	"char": "char", "nchar": "char", "character": "char",
+	"text": "text", "ntext": "text", "clob": "text", "mediumtext": "text", "longtext": "text",
+	"date": "date", "time": "time",
+	"datetime": "timestamp", "datetime2": "timestamp", "timestamp": "timestamp", "timestamptz": "timestamp",
+	"blob": "bytes", "longblob": "bytes", "bytea": "bytes", "varbinary": "bytes", "binary": "bytes", "image": "bytes",
+	"uuid": "uuid", "uniqueidentifier": "uuid",
+	"json": "json", "jsonb": "json",
+}
+
+// goTypeFamilies type families of the go types of model fields, nullable and pointer types use the family of their value
+var goTypeFamilies = map[string]string{
+	"bool": "bool",
+	"int8": "smallint", "int16": "smallint", "uint8": "smallint",
+	"int": "int", "int32": "int", "uint16": "int",
+	"int64": "bigint", "uint": "bigint", "uint32": "bigint", "uint64": "bigint",
+	"float32": "real", "float64": "double",
+	"string":    "string",
+	"time.Time": "timestamp",
+	"[]byte":    "bytes", "[]uint8": "bytes",
+	"uuid.UUID": "uuid", "json.RawMessage": "json",
+}
+
+// ddlNullableTypes value types of nullable types not known to the code generators
+var ddlNullableTypes = map[string]string{
+	"mysql.NullTime": "time.Time",
+	"pq.NullTime":    "time.Time",
+	"null.Int32":     "int32",
+	"sql.NullInt16":  "int16",
+	"sql.NullByte":   "uint8",
+	"uuid.NullUUID":  "uuid.UUID",
+}
+
+// LoadStructSchema parses the go files of the package in dir and returns the schema of the tables of the structs with a TableName
+// method or a field with a db, gorm, sql or ddl tag, structs only embedded in other structs are not tables. Column names, types,
+// keys, defaults and indexes are read from the gorm settings of the sql, gorm and ddl tags, the ddl tag also takes
+// references:table(column) to declare a foreign key.
+func LoadStructSchema(dir, sqlType string) (*SchemaSnapshot, error) {
+	sqlType = migrationSQLType(sqlType)
+	if _, ok := ddlTypes["int"][sqlType]; !ok {
+		return nil, fmt.Errorf("unsupported sql type: %s, must be one of [ mysql | postgres | mssql | sqlite3 ]", sqlType)
+	}
+
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool { return !strings.HasSuffix(fi.Name(), "_test.go") }, 0)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse %s: %v", dir, err)
+	}
+
+	structs := make(map[string]*ast.StructType)
+	tableNames := make(map[string]string)
+	embedded := make(map[string]bool)
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				switch decl := decl.(type) {
+				case *ast.GenDecl:
+					for _, spec := range decl.Specs {
+						if ts, ok := spec.(*ast.TypeSpec); ok {
+							if st, ok := ts.Type.(*ast.StructType); ok {
+								structs[ts.Name.Name] = st
+								for _, field := range st.Fields.List {
+									if len(field.Names) == 0 {
+										embedded[strings.TrimPrefix(exprString(field.Type), "*")] = true
+									}
+								}
+							}
+						}
+					}
+				case *ast.FuncDecl:
+					if name, tableName, ok := structTableName(decl); ok {
+						tableNames[name] = tableName
+					}
+				}
+			}
+		}
+	}
+
+	snapshot := &SchemaSnapshot{SQLType: sqlType}
+	for name, st := range structs {
+		tableName, hasTableName := tableNames[name]
+		if !hasTableName && (embedded[name] || !hasColumnTags(st)) {
+			continue
+		}
+		if !hasTableName {
+			tableName = inflection.Plural(strcase.ToSnake(name))
+		}
+
+		t := &TableSchema{Name: tableName}
+		addStructColumns(t, st, structs, sqlType)
+		if len(t.Columns) == 0 {
+			continue
+		}
+
+		if len(t.PrimaryKey) == 0 {
+			if c := t.column("id"); c != nil {
+				t.PrimaryKey = []string{c.Name}
+			}
+		}
+		for _, key := range t.PrimaryKey {
+			t.column(key).Nullable = false
+		}
+
+		sort.Slice(t.Indexes, func(i, j int) bool { return t.Indexes[i].Name < t.Indexes[j].Name })
+		snapshot.Tables = append(snapshot.Tables, t)
+	}
+
+	sort.Slice(snapshot.Tables, func(i, j int) bool { return snapshot.Tables[i].Name < snapshot.Tables[j].Name })
+	return snapshot, nil
+}
+
+// CreateSchemaDDL create table, index and foreign key statements of the tables of the snapshot in the dialect of the snapshot
+func CreateSchemaDDL(s *SchemaSnapshot) string {
+	d := migrationDialect(migrationSQLType(s.SQLType))
+	return migrationSQL(d.diff(&SchemaSnapshot{SQLType: s.SQLType}, s))
+}
+
+// structTableName struct name and table name of a TableName method returning a string literal
+func structTableName(fn *ast.FuncDecl) (string, string, bool) {
+	if fn.Name.Name != "TableName" || fn.Recv == nil || len(fn.Recv.List) != 1 || fn.Body == nil || len(fn.Body.List) != 1 {
+		return "", "", false
+	}
+
+	recv := fn.Recv.List[0].Type
+	if star, ok := recv.(*ast.StarExpr); ok {
+		recv = star.X
+	}
+	ident, ok := recv.(*ast.Ident)
+	if !ok {
+		return "", "", false
+	}
+
+	ret, ok := fn.Body.List[0].(*ast.ReturnStmt)
+	if !ok || len(ret.Results) != 1 {
+		return "", "", false
+	}
+	lit, ok := ret.Results[0].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", "", false
+	}
+
+	tableName, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return "", "", false
+	}
+	return ident.Name, tableName, true
+}
+
+func hasColumnTags(st *ast.StructType) bool {
+	for _, field := range st.Fields.List {
+		tag := fieldTag(field)
+		for _, key := range []string{"db", "gorm", "sql", "ddl"} {
+			if _, ok := tag.Lookup(key); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func fieldTag(field *ast.Field) reflect.StructTag {
+	if field.Tag == nil {
+		return ""
+	}
+	tag, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return ""
+	}
+	return reflect.StructTag(tag)
+}
+
+// fieldSettings gorm style settings of the sql, gorm and ddl tags of a field keyed by upper case name, later tags override
+func fieldSettings(tag reflect.StructTag) map[string]string {
+	settings := make(map[string]string)
+	for _, key := range []string{"sql", "gorm", "ddl"} {
+		for _, setting := range strings.Split(tag.Get(key), ";") {
+			setting = strings.TrimSpace(setting)
+			if setting == "" {
+				continue
+			}
+
+			parts := strings.SplitN(setting, ":", 2)
+			name := strings.ToUpper(strings.TrimSpace(parts[0]))
+			if len(parts) == 2 {
+				settings[name] = strings.TrimSpace(parts[1])
+			} else {
+				settings[name] = name
+			}
+		}
+	}
+	return settings
+}
+
+// addStructColumns adds a column for each exported field of the struct, embedded structs of the package and gorm.Model add their
+// fields and fields holding other structs or slices are left out as associations
+func addStructColumns(t *TableSchema, st *ast.StructType, structs map[string]*ast.StructType, sqlType string) {
+	for _, field := range st.Fields.List {
+		tag := fieldTag(field)
+		settings := fieldSettings(tag)
+		if tag.Get("db") == "-" || settings["-"] != "" {
+			continue
+		}
+
+		goType := exprString(field.Type)
+		if len(field.Names) == 0 {
+			switch {
+			case goType == "gorm.Model":
+				addGormModelColumns(t, sqlType)
+			case structs[strings.TrimPrefix(goType, "*")] != nil:
+				addStructColumns(t, structs[strings.TrimPrefix(goType, "*")], structs, sqlType)
+			}
+			continue
+		}
+
+		for _, name := range field.Names {
+			if !name.IsExported() {
+				continue
+			}
+			addStructColumn(t, name.Name, goType, tag, settings, sqlType)
+		}
+	}
+}
+
+func addGormModelColumns(t *TableSchema, sqlType string) {
+	addStructColumn(t, "ID", "uint", "", map[string]string{"PRIMARY_KEY": "PRIMARY_KEY", "AUTO_INCREMENT": "AUTO_INCREMENT"}, sqlType)
+	addStructColumn(t, "CreatedAt", "time.Time", "", map[string]string{}, sqlType)
+	addStructColumn(t, "UpdatedAt", "time.Time", "", map[string]string{}, sqlType)
+	addStructColumn(t, "DeletedAt", "*time.Time", "", map[string]string{"INDEX": "INDEX"}, sqlType)
+}
+
+func addStructColumn(t *TableSchema, fieldName, goType string, tag reflect.StructTag, settings map[string]string, sqlType string) {
+	valueType, nullable := ddlValueType(goType)
+
+	columnType, family, ok := ddlColumnType(valueType, settings, sqlType)
+	if !ok {
+		return
+	}
+
+	name := strcase.ToSnake(fieldName)
+	if dbName := strings.Split(tag.Get("db"), ",")[0]; dbName != "" {
+		name = dbName
+	}
+	if column := settings["COLUMN"]; column != "" {
+		name = column
+	}
+
+	_, notNull := settings["NOT NULL"]
+	c := &ColumnSchema{
+		Name:          name,
+		Type:          columnType,
+		Nullable:      nullable && !notNull,
+		Default:       ddlDefault(family, settings["DEFAULT"], sqlType),
+		AutoIncrement: settings["AUTO_INCREMENT"] != "" || settings["AUTOINCREMENT"] != "",
+	}
+	t.Columns = append(t.Columns, c)
+
+	if settings["PRIMARY_KEY"] != "" || settings["PRIMARYKEY"] != "" {
+		t.PrimaryKey = append(t.PrimaryKey, name)
+	}
+
+	if _, ok := settings["UNIQUE"]; ok {
+		addStructIndex(t, fmt.Sprintf("uix_%s_%s", t.Name, name), name, true)
+	}
+	for _, key := range []string{"INDEX", "UNIQUE_INDEX"} {
+		indexName, ok := settings[key]
+		if !ok {
+			continue
+		}
+
+		unique := key == "UNIQUE_INDEX"
+		if indexName == key {
+			prefix := "idx"
+			if unique {
+				prefix = "uix"
+			}
+			indexName = fmt.Sprintf("%s_%s_%s", prefix, t.Name, name)
+		}
+		for _, indexName := range strings.Split(indexName, ",") {
+			addStructIndex(t, indexName, name, unique)
+		}
+	}
+
+	if ref := settings["REFERENCES"]; ref != "" {
+		refTable, refColumn := ref, "id"
+		if i := strings.Index(ref, "("); i > 0 && strings.HasSuffix(ref, ")") {
+			refTable, refColumn = ref[:i], ref[i+1:len(ref)-1]
+		}
+		t.ForeignKeys = append(t.ForeignKeys, &ForeignKeySchema{
+			Name:      fmt.Sprintf("fk_%s_%s", t.Name, name),
+			Column:    name,
+			RefTable:  strings.TrimSpace(refTable),
+			RefColumn: strings.TrimSpace(refColumn),
+		})
+	}
+}
+
+// addStructIndex adds the column to the index with the name, fields sharing an index name make up a composite index
+func addStructIndex(t *TableSchema, name, column string, unique bool) {
+	if idx := t.index(name); idx != nil {
+		idx.Columns = append(idx.Columns, column)
+		return
+	}
+	t.Indexes = append(t.Indexes, &IndexSchema{Name: name, Columns: []string{column}, Unique: unique})
+}
+
+// ddlValueType type of the value of a field and whether the field is nullable, pointers, nullable types and slices hold NULL
+func ddlValueType(goType string) (string, bool) {
+	if valueType, ok := ddlNullableTypes[goType]; ok {
+		return valueType, true
+	}
+	if isNullableGoType(goType) {
+		return goBaseType(goType), true
+	}
+	return goType, strings.HasPrefix(goType, "[]")
+}
+
+// ddlColumnType column type and type family of a field in the dialect, the type and size settings take precedence over the go
+// type of the field, returns false for fields that do not map to a column
+func ddlColumnType(goType string, settings map[string]string, sqlType string) (string, string, bool) {
+	size, _ := strconv.Atoi(settings["SIZE"])
+
+	if typ := settings["TYPE"]; typ != "" {
+		name, typeLen := ParseSQLType(typ)
+		family, ok := sqlTypeFamilies[name]
+		if !ok || (strings.Contains(typ, "(") && typeLen < 0) {
+			return typ, "", true
+		}
+		if typeLen > 0 {
+			size = int(typeLen)
+		}
+		return ddlFamilyType(family, size, sqlType), family, true
+	}
+
+	family, ok := goTypeFamilies[goType]
+	if !ok {
+		return "", "", false
+	}
+	if family == "string" && size > 0 {
+		family = "varchar"
+	}
+	return ddlFamilyType(family, size, sqlType), family, true
+}
+
+// ddlDefault default of a column in the dialect, boolean defaults are written as true or false for postgres and 1 or 0 for
+// sql server
+func ddlDefault(family, value, sqlType string) string {
+	if family != "bool" {
+		return value
+	}
+
+	switch strings.ToLower(value) {
+	case "true", "1":
+		if sqlType == "postgres" {
+			return "true"
+		}
+		return "1"
+	case "false", "0":
+		if sqlType == "postgres" {
+			return "false"
+		}
+		return "0"
+	}
+	return value
+}
+
+func ddlFamilyType(family string, size int, sqlType string) string {
+	typ := ddlTypes[family][sqlType]
+	if strings.Contains(typ, "%d") {
+		if size <= 0 {
+			return ddlTypes["string"][sqlType]
+		}
+		return fmt.Sprintf(typ, size)
+	}
+	return typ
+}
+
+// exprString source of a type expression such as *time.Time or []byte
+func exprString(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		return expr.Name
+	case *ast.StarExpr:
+		return "*" + exprString(expr.X)
+	case *ast.SelectorExpr:
+		return exprString(expr.X) + "." + expr.Sel.Name
+	case *ast.ArrayType:
+		if expr.Len == nil {
+			return "[]" + exprString(expr.Elt)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,104 @@
+package dbmeta
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const structDDLTestModels = `package model
+
+import "database/sql"
+
+type Audit struct {
+	CreatedBy string ` + "`gorm:\"size:40\"`" + `
+}
+
+type Author struct {
+	ID   int64          ` + "`gorm:\"primary_key;AUTO_INCREMENT\"`" + `
+	Name sql.NullString ` + "`gorm:\"column:full_name;type:NVARCHAR(80);unique\"`" + `
+}
+
+type Book struct {
+	BookID   int     ` + "`db:\"book_id\" gorm:\"primary_key\"`" + `
+	Active   bool    ` + "`db:\"active\" gorm:\"default:1\"`" + `
+	AuthorID int64   ` + "`db:\"author_id\" ddl:\"references:authors(id);index\"`" + `
+	Author   *Author
+	Cover    []byte  ` + "`db:\"cover\"`" + `
+	Skipped  string  ` + "`db:\"-\"`" + `
+	Audit
+}
+
+func (b *Book) TableName() string {
+	return "library_books"
+}
+`
+
+func Test_LoadStructSchema(t *testing.T) {
+	dir, err := ioutil.TempDir("", "structddl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "model.go"), []byte(structDDLTestModels), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		sqlType  string
+		expected string
+	}{
+		{"postgres", `CREATE TABLE "authors" (
+	"id" bigint GENERATED BY DEFAULT AS IDENTITY NOT NULL,
+	"full_name" varchar(80),
+	PRIMARY KEY ("id")
+);
+CREATE TABLE "library_books" (
+	"book_id" integer NOT NULL,
+	"active" boolean NOT NULL DEFAULT true,
+	"author_id" bigint NOT NULL,
+	"cover" bytea,
+	"created_by" varchar(40) NOT NULL,
+	PRIMARY KEY ("book_id")
+);
+CREATE UNIQUE INDEX "uix_authors_full_name" ON "authors" ("full_name");
+CREATE INDEX "idx_library_books_author_id" ON "library_books" ("author_id");
+ALTER TABLE "library_books" ADD CONSTRAINT "fk_library_books_author_id" FOREIGN KEY ("author_id") REFERENCES "authors" ("id");
+`},
+		{"mssql", `CREATE TABLE [authors] (
+	[id] bigint IDENTITY(1,1) NOT NULL,
+	[full_name] nvarchar(80) NULL,
+	PRIMARY KEY ([id])
+);
+CREATE TABLE [library_books] (
+	[book_id] int NOT NULL,
+	[active] bit NOT NULL DEFAULT 1,
+	[author_id] bigint NOT NULL,
+	[cover] varbinary(max) NULL,
+	[created_by] nvarchar(40) NOT NULL,
+	PRIMARY KEY ([book_id])
+);
+CREATE UNIQUE INDEX [uix_authors_full_name] ON [authors] ([full_name]);
+CREATE INDEX [idx_library_books_author_id] ON [library_books] ([author_id]);
+ALTER TABLE [library_books] ADD CONSTRAINT [fk_library_books_author_id] FOREIGN KEY ([author_id]) REFERENCES [authors] ([id]);
+`},
+	}
+
+	for _, tt := range tests {
+		s, err := LoadStructSchema(dir, tt.sqlType)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.sqlType, err)
+		}
+
+		ddl := CreateSchemaDDL(s)
+		if ddl != tt.expected {
+			t.Errorf("%s: expect: %s, but got %s", tt.sqlType, tt.expected, ddl)
+		}
+	}
+
+	if _, err := LoadStructSchema(dir, "oracle"); err == nil {
+		t.Errorf("oracle: expect an error")
+	}
+}
@@ -0,0 +1,226 @@
+package dbmeta
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/inflection"
+)
+
+// sqliteTypes sqlite column types of the go types of the model fields, used to create the tables of the generated tests
+var sqliteTypes = map[string]string{
+	"int":       "INTEGER",
+	"int32":     "INTEGER",
+	"int64":     "INTEGER",
+	"bool":      "BOOLEAN",
+	"float32":   "REAL",
+	"float64":   "REAL",
+	"string":    "TEXT",
+	"[]byte":    "BLOB",
+	"time.Time": "DATETIME",
+}
+
+// sqliteDefault sqlite DEFAULT clause of a column, only number and quoted string literals are carried over
+func sqliteDefault(defaultValue string) (string, bool) {
+	value := strings.TrimSpace(defaultValue)
+	for len(value) > 1 && value[0] == '(' && value[len(value)-1] == ')' {
+		value = strings.TrimSpace(value[1 : len(value)-1])
+	}
+
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return value, true
+	}
+
+	if len(value) > 1 && value[0] == '\'' && value[len(value)-1] == '\'' && !strings.Contains(value, "::") {
+		return value, true
+	}
+	return "", false
+}
+
+// SqliteDDL sqlite CREATE TABLE statement of the table derived from the model fields, used by the generated tests to create the
+// table in an in memory database. Foreign keys are left out, columns with a default that cannot be carried over are nullable.
+func (m *ModelInfo) SqliteDDL() string {
+	primaryKeys := m.PrimaryKeyFields()
+
+	var columns []string
+	for _, f := range m.CodeFields {
+		name := strconv.Quote(f.ColumnMeta.Name())
+
+		if len(primaryKeys) == 1 && f == primaryKeys[0] && f.ColumnMeta.IsAutoIncrement() {
+			columns = append(columns, fmt.Sprintf("%s INTEGER PRIMARY KEY AUTOINCREMENT", name))
+			continue
+		}
+
+		column := name
+		if typ, ok := sqliteTypes[goBaseType(f.GoFieldType)]; ok {
+			column += " " + typ
+		}
+
+		defaultValue, hasDefault := sqliteDefault(f.ColumnMeta.DefaultValue())
+		if !f.ColumnMeta.Nullable() && (hasDefault || f.ColumnMeta.DefaultValue() == "") {
+			column += " NOT NULL"
+		}
+
+		if hasDefault {
+			column += " DEFAULT " + defaultValue
+		}
+		columns = append(columns, column)
+	}
+
+	if len(primaryKeys) > 1 || (len(primaryKeys) == 1 && !primaryKeys[0].ColumnMeta.IsAutoIncrement()) {
+		var names []string
+		for _, f := range primaryKeys {
+			names = append(names, strconv.Quote(f.ColumnMeta.Name()))
+		}
+		columns = append(columns, fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(names, ", ")))
+	}
+
+	return fmt.Sprintf("CREATE TABLE %s (\n\t%s\n)", strconv.Quote(m.TableName), strings.Join(columns, ",\n\t"))
+}
+
+// testValueSkipped reports if the generated tests leave the field to the db or the dao, auto increment keys, timestamps, soft
+// delete and version columns are maintained for the record
+func (m *ModelInfo) testValueSkipped(f *FieldInfo) bool {
+	if f.ColumnMeta.IsAutoIncrement() {
+		return true
+	}
+
+	switch f {
+	case m.CreatedAtField, m.UpdatedAtField, m.SoftDeleteField, m.VersionField:
+		return true
+	}
+	return strings.HasPrefix(f.GoFieldType, "*") || f.GoFieldType == "interface{}"
+}
+
+// testLiteral go literal of the fake value of a field, fitted to the validation of the field so the record passes Validate
+func testLiteral(f *FieldInfo, value reflect.Value) (string, bool) {
+	v := f.Validation
+
+	switch goBaseType(f.GoFieldType) {
+	case "string":
+		s := fmt.Sprint(value.Interface())
+		if v != nil && len(v.Enum) > 0 {
+			s = v.Enum[0]
+		}
+		if v != nil && v.MaxLength > 0 && int64(len([]rune(s))) > v.MaxLength {
+			s = string([]rune(s)[:v.MaxLength])
+		}
+		return strconv.Quote(s), true
+
+	case "[]byte":
+		if b, ok := value.Interface().([]byte); ok {
+			return fmt.Sprintf("[]byte(%q)", b), true
+		}
+		return fmt.Sprintf("[]byte(%q)", fmt.Sprint(value.Interface())), true
+
+	case "int", "int32", "int64":
+		if value.Kind() < reflect.Int || value.Kind() > reflect.Int64 {
+			return "", false
+		}
+		i := value.Int()
+		if v != nil && v.HasRange && (i < v.Min || i > v.Max) {
+			i = v.Min + (i%(v.Max-v.Min+1)+(v.Max-v.Min+1))%(v.Max-v.Min+1)
+		}
+		return strconv.FormatInt(i, 10), true
+
+	case "float32", "float64":
+		if value.Kind() != reflect.Float32 && value.Kind() != reflect.Float64 {
+			return "", false
+		}
+		return strconv.FormatFloat(value.Float(), 'g', -1, 64), true
+
+	case "bool":
+		if value.Kind() != reflect.Bool {
+			return "", false
+		}
+		return strconv.FormatBool(value.Bool()), true
+
+	case "time.Time":
+		t, ok := value.Interface().(time.Time)
+		if !ok {
+			return "", false
+		}
+		t = t.UTC()
+		return fmt.Sprintf("time.Date(%d, %d, %d, %d, %d, %d, 0, time.UTC)", t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second()), true
+	}
+	return "", false
+}
+
+// TestRecordCode composite literal of a record of the table filled with the fake values gen built for the table, used by the
+// generated tests to add a record
+func (m *ModelInfo) TestRecordCode(modelPackageName string) string {
+	instance := reflect.Indirect(reflect.ValueOf(m.Instance))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "&%s.%s{", modelPackageName, m.StructName)
+	for _, f := range m.CodeFields {
+		if m.testValueSkipped(f) || instance.Kind() != reflect.Struct {
+			continue
+		}
+
+		value := instance.FieldByName(f.GoFieldName)
+		if !value.IsValid() {
+			continue
+		}
+
+		literal, ok := testLiteral(f, value)
+		if !ok {
+			continue
+		}
+
+		if n, ok := nullableTypes[f.GoFieldType]; ok {
+			literal = fmt.Sprintf(n.Ctor, literal, "true")
+		}
+		fmt.Fprintf(&b, "\n\t\t%s: %s,", f.GoFieldName, literal)
+	}
+	b.WriteString("\n\t}")
+	return b.String()
+}
+
+// TestCheckField field the generated tests update and compare after reading the record back, the first plain string column
+// that is not a key, constrained to enum values or maintained by the dao. Nil when the table has no such column.
+func (m *ModelInfo) TestCheckField() *FieldInfo {
+	for _, f := range m.CodeFields {
+		if f.GoFieldType != "string" || f.PrimaryKeyArgName != "" || m.testValueSkipped(f) {
+			continue
+		}
+		if f.Validation != nil && len(f.Validation.Enum) > 0 {
+			continue
+		}
+		return f
+	}
+	return nil
+}
+
+// TestUpdateValue go literal of the value the generated tests update the field to, see TestCheckField
+func (f *FieldInfo) TestUpdateValue() string {
+	s := "updated"
+	if f.Validation != nil && f.Validation.MaxLength > 0 && int64(len(s)) > f.Validation.MaxLength {
+		s = s[:f.Validation.MaxLength]
+	}
+	return strconv.Quote(s)
+}
+
+// TestReferenceCode statement pointing the foreign key of the record dst at the first record of the referenced table, so the
+// generated tests pass against a supplied database enforcing foreign keys. The fake value is kept when the referenced table is
+// empty, daoPrefix qualifies the dao functions outside of the dao package.
+func (fk *ForeignKeyInfo) TestReferenceCode(dst, daoPrefix string) string {
+	if strings.HasPrefix(fk.Field.GoFieldType, "*") || fk.Field.GoFieldType == "interface{}" {
+		return ""
+	}
+
+	value, ok := convertGoValue("refs[0]."+fk.Column.GoFieldName, goBaseType(fk.Column.GoFieldType), goBaseType(fk.Field.GoFieldType))
+	if !ok {
+		return ""
+	}
+
+	if n, ok := nullableTypes[fk.Field.GoFieldType]; ok {
+		value = fmt.Sprintf(n.Ctor, value, "true")
+	}
+
+	return fmt.Sprintf("\n\tif refs, _, err := %sGetAll%s(context.Background(), 0, 1, \"\"); err == nil && len(refs) > 0 {\n\t\t%s.%s = %s\n\t}",
+		daoPrefix, inflection.Plural(fk.Table.StructName), dst, fk.Field.GoFieldName, value)
+}
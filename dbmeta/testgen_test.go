@@ -0,0 +1,72 @@
+package dbmeta
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_SqliteDDL(t *testing.T) {
+	id := &FieldInfo{GoFieldName: "ID", GoFieldType: "int", PrimaryKeyArgName: "argID",
+		ColumnMeta: &testColumn{name: "id", primaryKey: true, autoIncrement: true}}
+	name := &FieldInfo{GoFieldName: "Name", GoFieldType: "string", ColumnMeta: &testColumn{name: "name"}}
+	status := &FieldInfo{GoFieldName: "Status", GoFieldType: "string", ColumnMeta: &testColumn{name: "status", defaultValue: "'new'"}}
+	price := &FieldInfo{GoFieldName: "Price", GoFieldType: "sql.NullFloat64", ColumnMeta: &testColumn{name: "price", nullable: true}}
+	created := &FieldInfo{GoFieldName: "Created", GoFieldType: "time.Time", ColumnMeta: &testColumn{name: "created", defaultValue: "CURRENT_TIMESTAMP"}}
+	orders := &ModelInfo{TableName: "orders", CodeFields: []*FieldInfo{id, name, status, price, created}}
+
+	expected := "CREATE TABLE \"orders\" (\n\t\"id\" INTEGER PRIMARY KEY AUTOINCREMENT,\n\t\"name\" TEXT NOT NULL,\n\t\"status\" TEXT NOT NULL DEFAULT 'new'," +
+		"\n\t\"price\" REAL,\n\t\"created\" DATETIME\n)"
+	if ddl := orders.SqliteDDL(); ddl != expected {
+		t.Errorf("orders: expect: %q, but got %q", expected, ddl)
+	}
+
+	orderID := &FieldInfo{GoFieldName: "OrderID", GoFieldType: "int64", PrimaryKeyArgName: "argOrderID",
+		ColumnMeta: &testColumn{name: "order_id", primaryKey: true}}
+	line := &FieldInfo{GoFieldName: "Line", GoFieldType: "int32", PrimaryKeyArgName: "argLine",
+		ColumnMeta: &testColumn{name: "line", primaryKey: true}}
+	lines := &ModelInfo{TableName: "order_lines", CodeFields: []*FieldInfo{orderID, line}}
+
+	expected = "CREATE TABLE \"order_lines\" (\n\t\"order_id\" INTEGER NOT NULL,\n\t\"line\" INTEGER NOT NULL,\n\tPRIMARY KEY (\"order_id\", \"line\")\n)"
+	if ddl := lines.SqliteDDL(); ddl != expected {
+		t.Errorf("order_lines: expect: %q, but got %q", expected, ddl)
+	}
+}
+
+func Test_TestRecordCode(t *testing.T) {
+	id := &FieldInfo{GoFieldName: "ID", GoFieldType: "int", PrimaryKeyArgName: "argID",
+		ColumnMeta: &testColumn{name: "id", primaryKey: true, autoIncrement: true}}
+	code := &FieldInfo{GoFieldName: "Code", GoFieldType: "string", ColumnMeta: &testColumn{name: "code"},
+		Validation: &FieldValidation{MaxLength: 3}}
+	kind := &FieldInfo{GoFieldName: "Kind", GoFieldType: "null.String", ColumnMeta: &testColumn{name: "kind"},
+		Validation: &FieldValidation{Enum: []string{"a", "b"}}}
+	level := &FieldInfo{GoFieldName: "Level", GoFieldType: "int", ColumnMeta: &testColumn{name: "level"},
+		Validation: &FieldValidation{HasRange: true, Min: 0, Max: 9}}
+	due := &FieldInfo{GoFieldName: "Due", GoFieldType: "sql.NullTime", ColumnMeta: &testColumn{name: "due"}}
+	updated := &FieldInfo{GoFieldName: "UpdatedAt", GoFieldType: "time.Time", ColumnMeta: &testColumn{name: "updated_at"}}
+
+	instance := &struct {
+		ID        int
+		Code      string
+		Kind      string
+		Level     int
+		Due       time.Time
+		UpdatedAt time.Time
+	}{1, "abcdef", "zzz", 42, time.Date(2020, 5, 6, 7, 8, 9, 10, time.UTC), time.Now()}
+
+	m := &ModelInfo{StructName: "Job", CodeFields: []*FieldInfo{id, code, kind, level, due, updated}, Instance: instance,
+		UpdatedAtField: updated}
+
+	expected := "&model.Job{\n\t\tCode: \"abc\",\n\t\tKind: null.NewString(\"a\", true),\n\t\tLevel: 2," +
+		"\n\t\tDue: sql.NullTime{Time: time.Date(2020, 5, 6, 7, 8, 9, 0, time.UTC), Valid: true},\n\t}"
+	if record := m.TestRecordCode("model"); record != expected {
+		t.Errorf("expect: %q, but got %q", expected, record)
+	}
+
+	if f := m.TestCheckField(); f != code {
+		t.Errorf("expect Code as the check field, but got %+v", f)
+	}
+
+	if value := code.TestUpdateValue(); value != "\"upd\"" {
+		t.Errorf("expect: %q, but got %q", "\"upd\"", value)
+	}
+}
@@ -0,0 +1,94 @@
+package dbmeta
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jinzhu/inflection"
+)
+
+// typescriptTypes typescript types of the json types of the sql mappings
+var typescriptTypes = map[string]string{
+	"Boolean": "boolean",
+	"Integer": "number",
+	"Float":   "number",
+	"Text":    "string",
+}
+
+var typescriptIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// typescriptBaseType typescript type of the value of a field, times are sent as RFC 3339 strings and []byte as base64 strings
+func typescriptBaseType(f *FieldInfo) string {
+	switch goBaseType(f.GoFieldType) {
+	case "time.Time", "[]byte":
+		return "string"
+	case "interface{}":
+		return "unknown"
+	}
+
+	if f.SqlMapping != nil {
+		if typ, ok := typescriptTypes[f.SqlMapping.JSONType]; ok {
+			return typ
+		}
+	}
+	return "unknown"
+}
+
+// typescriptProperty name of the property of the field, the json name the model is marshaled with
+func typescriptProperty(f *FieldInfo, jsonAnnotation bool) string {
+	if jsonAnnotation {
+		return f.JSONFieldName
+	}
+	return f.GoFieldName
+}
+
+// TypescriptName property name of the field in the interface of the table, quoted when it is not an identifier
+func (f *FieldInfo) TypescriptName(jsonAnnotation bool) string {
+	name := typescriptProperty(f, jsonAnnotation)
+	if typescriptIdentifier.MatchString(name) {
+		return name
+	}
+	return strconv.Quote(name)
+}
+
+// TypescriptKey string literal of the property name of the field, used as a key type
+func (f *FieldInfo) TypescriptKey(jsonAnnotation bool) string {
+	return strconv.Quote(typescriptProperty(f, jsonAnnotation))
+}
+
+// TypescriptType typescript type of the field as marshaled by encoding/json, sql nullable types are sent as an object with a
+// Valid flag while guregu null types and pointers are sent as null
+func (f *FieldInfo) TypescriptType() string {
+	typ := typescriptBaseType(f)
+	if n, ok := nullableTypes[f.GoFieldType]; ok && strings.HasPrefix(f.GoFieldType, "sql.") {
+		return fmt.Sprintf("{ %s: %s; Valid: boolean }", n.Value, typ)
+	}
+
+	if isNullableGoType(f.GoFieldType) {
+		return typ + " | null"
+	}
+	return typ
+}
+
+// TypescriptArgName name of the parameter of a client function taking the field, used for primary keys
+func (f *FieldInfo) TypescriptArgName() string {
+	return graphqlName(f.GoFieldName)
+}
+
+// TypescriptReadOnlyFields fields maintained by the db or the dao, left out of the input type of the table
+func (m *ModelInfo) TypescriptReadOnlyFields() []*FieldInfo {
+	var fields []*FieldInfo
+	for _, f := range m.CodeFields {
+		if f.ColumnMeta.IsAutoIncrement() || f == m.CreatedAtField || f == m.UpdatedAtField || f == m.SoftDeleteField {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// TypescriptListName name of the client function returning a page of records of the table
+func (m *ModelInfo) TypescriptListName() string {
+	return "list" + inflection.Plural(m.StructName)
+}
@@ -0,0 +1,50 @@
+package dbmeta
+
+import (
+	"testing"
+)
+
+func Test_TypescriptType(t *testing.T) {
+	text := &SQLMapping{JSONType: "Text"}
+	integer := &SQLMapping{JSONType: "Integer"}
+
+	tests := []struct {
+		field    *FieldInfo
+		expected string
+	}{
+		{&FieldInfo{GoFieldType: "int64", SqlMapping: integer}, "number"},
+		{&FieldInfo{GoFieldType: "string", SqlMapping: text}, "string"},
+		{&FieldInfo{GoFieldType: "sql.NullString", SqlMapping: text}, "{ String: string; Valid: boolean }"},
+		{&FieldInfo{GoFieldType: "sql.NullInt64", SqlMapping: integer}, "{ Int64: number; Valid: boolean }"},
+		{&FieldInfo{GoFieldType: "null.Int", SqlMapping: integer}, "number | null"},
+		{&FieldInfo{GoFieldType: "*time.Time", SqlMapping: text}, "string | null"},
+		{&FieldInfo{GoFieldType: "[]byte", SqlMapping: text}, "string"},
+		{&FieldInfo{GoFieldType: "interface{}"}, "unknown"},
+	}
+
+	for _, tt := range tests {
+		typ := tt.field.TypescriptType()
+		if typ != tt.expected {
+			t.Errorf("%s: expect: %s, but got %s", tt.field.GoFieldType, tt.expected, typ)
+		}
+	}
+}
+
+func Test_TypescriptName(t *testing.T) {
+	tests := []struct {
+		field          *FieldInfo
+		jsonAnnotation bool
+		expected       string
+	}{
+		{&FieldInfo{GoFieldName: "AlbumID", JSONFieldName: "album_id"}, true, "album_id"},
+		{&FieldInfo{GoFieldName: "AlbumID", JSONFieldName: "album_id"}, false, "AlbumID"},
+		{&FieldInfo{GoFieldName: "UnitPrice", JSONFieldName: "unit-price"}, true, "\"unit-price\""},
+	}
+
+	for _, tt := range tests {
+		name := tt.field.TypescriptName(tt.jsonAnnotation)
+		if name != tt.expected {
+			t.Errorf("%s: expect: %s, but got %s", tt.field.GoFieldName, tt.expected, name)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package dbmeta
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// integerRange range of values a column of an integer sql type can hold
+type integerRange struct {
+	Min int64
+	Max int64
+}
+
+var integerRanges = map[string]integerRange{
+	"tinyint":            {-128, 127},
+	"unsigned tinyint":   {0, 255},
+	"smallint":           {-32768, 32767},
+	"unsigned smallint":  {0, 65535},
+	"int2":               {-32768, 32767},
+	"smallserial":        {1, 32767},
+	"mediumint":          {-8388608, 8388607},
+	"unsigned mediumint": {0, 16777215},
+	"int":                {-2147483648, 2147483647},
+	"unsigned int":       {0, 4294967295},
+	"integer":            {-2147483648, 2147483647},
+	"int4":               {-2147483648, 2147483647},
+	"serial":             {1, 2147483647},
+}
+
+// sqlIntegerRange returns the range of an integer sql type, sqlite integers are always 64 bit and ms sql tinyint is unsigned
+func sqlIntegerRange(sqlType, dbType string) (integerRange, bool) {
+	dbType = strings.ToLower(strings.TrimSpace(dbType))
+	switch {
+	case sqlType == "sqlite3":
+		return integerRange{}, false
+	case sqlType == "mssql" && dbType == "tinyint":
+		return integerRange{0, 255}, true
+	}
+
+	r, ok := integerRanges[dbType]
+	return r, ok
+}
+
+// createFieldValidation derives the constraints of a field from the column, returns nil when the column is unconstrained.
+// NOT NULL columns without a default are required, only string and timestamp fields are checked as a zero number is a valid value.
+func createFieldValidation(sqlType string, col ColumnMeta, goType string) *FieldValidation {
+	v := &FieldValidation{}
+
+	switch goType {
+	case "string", "time.Time":
+		v.Required = !col.Nullable() && col.DefaultValue() == ""
+	}
+
+	switch goType {
+	case "string", "sql.NullString", "null.String":
+		if col.ColumnLength() > 0 {
+			v.MaxLength = col.ColumnLength()
+		}
+		v.Enum = col.EnumValues()
+
+	case "int", "int32", "int64", "sql.NullInt64", "null.Int":
+		if r, ok := sqlIntegerRange(sqlType, col.DatabaseTypeName()); ok {
+			v.HasRange, v.Min, v.Max = true, r.Min, r.Max
+		}
+	}
+
+	if !v.Required && v.MaxLength == 0 && !v.HasRange && len(v.Enum) == 0 {
+		return nil
+	}
+	return v
+}
+
+// createValidateAnnotation go-playground/validator tag for the constraints, validator does not handle sql and null types
+// without registering custom types so only plain types are tagged.
+func createValidateAnnotation(v *FieldValidation, goType string) string {
+	if v == nil {
+		return ""
+	}
+
+	switch goType {
+	case "string", "time.Time", "int", "int32", "int64":
+	default:
+		return ""
+	}
+
+	var rules []string
+	if v.Required {
+		rules = append(rules, "required")
+	}
+
+	if v.MaxLength > 0 {
+		rules = append(rules, fmt.Sprintf("max=%d", v.MaxLength))
+	}
+
+	if v.HasRange {
+		rules = append(rules, fmt.Sprintf("min=%d", v.Min), fmt.Sprintf("max=%d", v.Max))
+	}
+
+	if len(v.Enum) > 0 && enumTaggable(v.Enum) {
+		rules = append(rules, fmt.Sprintf("oneof=%s", strings.Join(v.Enum, " ")))
+	}
+
+	if len(rules) == 0 {
+		return ""
+	}
+
+	if !v.Required {
+		rules = append([]string{"omitempty"}, rules...)
+	}
+	return fmt.Sprintf("validate:\"%s\"", strings.Join(rules, ","))
+}
+
+// enumTaggable oneof values are space separated and cannot contain tag delimiters
+func enumTaggable(values []string) bool {
+	for _, value := range values {
+		if value == "" || strings.ContainsAny(value, " ,|\"`") {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidationCode statements checking the field constraints in the model Validate func, each violation is added to errs
+func (f *FieldInfo) ValidationCode(receiver string) string {
+	v := f.Validation
+	if v == nil {
+		return ""
+	}
+
+	value := fmt.Sprintf("%s.%s", receiver, f.GoFieldName)
+	valid := ""
+	switch f.GoFieldType {
+	case "sql.NullString", "null.String":
+		valid = value + ".Valid && "
+		value = value + ".String"
+	case "sql.NullInt64", "null.Int":
+		valid = value + ".Valid && "
+		value = value + ".Int64"
+	}
+
+	buf := bytes.Buffer{}
+	check := func(cond, message string) {
+		buf.WriteString(fmt.Sprintf("\n\tif %s {\n\t\terrs.Add(%q, %q)\n\t}\n", cond, f.JSONFieldName, message))
+	}
+
+	if v.Required {
+		if f.GoFieldType == "time.Time" {
+			check(value+".IsZero()", "is required")
+		} else {
+			check(value+` == ""`, "is required")
+		}
+	}
+
+	if v.MaxLength > 0 {
+		check(fmt.Sprintf("%sutf8.RuneCountInString(%s) > %d", valid, value, v.MaxLength),
+			fmt.Sprintf("must be at most %d characters", v.MaxLength))
+	}
+
+	if v.HasRange {
+		check(fmt.Sprintf("%s(%s < %d || %s > %d)", valid, value, v.Min, value, v.Max),
+			fmt.Sprintf("must be between %d and %d", v.Min, v.Max))
+	}
+
+	if len(v.Enum) > 0 {
+		cond := fmt.Sprintf(`%s%s != ""`, valid, value)
+		for _, e := range v.Enum {
+			cond = cond + fmt.Sprintf(" && %s != %q", value, e)
+		}
+		check(cond, fmt.Sprintf("must be one of %s", strings.Join(v.Enum, ", ")))
+	}
+
+	return buf.String()
+}
@@ -0,0 +1,64 @@
+package dbmeta
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_mysqlParseEnumValues(t *testing.T) {
+	tests := []struct {
+		colDDL   string
+		expected []string
+	}{
+		{" enum('small','medium','large') NOT NULL DEFAULT 'small'", []string{"small", "medium", "large"}},
+		{" ENUM('it''s','a,b') DEFAULT NULL", []string{"it's", "a,b"}},
+		{" varchar(20) NOT NULL", nil},
+	}
+
+	for _, tt := range tests {
+		values := mysqlParseEnumValues(tt.colDDL)
+		if !reflect.DeepEqual(values, tt.expected) {
+			t.Errorf("%s: expect: %v, but got %v", tt.colDDL, tt.expected, values)
+		}
+	}
+}
+
+func Test_createFieldValidation(t *testing.T) {
+	tests := []struct {
+		sqlType  string
+		col      *testColumn
+		goType   string
+		expected *FieldValidation
+		tag      string
+	}{
+		{"mysql", &testColumn{name: "name", dbType: "VARCHAR", length: 20}, "string",
+			&FieldValidation{Required: true, MaxLength: 20}, `validate:"required,max=20"`},
+		{"mysql", &testColumn{name: "name", dbType: "VARCHAR", length: 20, defaultValue: "x"}, "string",
+			&FieldValidation{MaxLength: 20}, `validate:"omitempty,max=20"`},
+		{"mysql", &testColumn{name: "name", dbType: "VARCHAR", nullable: true, length: 20}, "sql.NullString",
+			&FieldValidation{MaxLength: 20}, ""},
+		{"mysql", &testColumn{name: "qty", dbType: "TINYINT"}, "int",
+			&FieldValidation{HasRange: true, Min: -128, Max: 127}, `validate:"omitempty,min=-128,max=127"`},
+		{"mysql", &testColumn{name: "qty", dbType: "UNSIGNED SMALLINT"}, "int",
+			&FieldValidation{HasRange: true, Max: 65535}, `validate:"omitempty,min=0,max=65535"`},
+		{"mssql", &testColumn{name: "qty", dbType: "TINYINT"}, "int",
+			&FieldValidation{HasRange: true, Max: 255}, `validate:"omitempty,min=0,max=255"`},
+		{"sqlite3", &testColumn{name: "qty", dbType: "INTEGER"}, "int", nil, ""},
+		{"mysql", &testColumn{name: "size", dbType: "CHAR", defaultValue: "small", enumValues: []string{"small", "large"}}, "string",
+			&FieldValidation{Enum: []string{"small", "large"}}, `validate:"omitempty,oneof=small large"`},
+		{"mysql", &testColumn{name: "size", dbType: "CHAR", defaultValue: "a b", enumValues: []string{"a b", "c"}}, "string",
+			&FieldValidation{Enum: []string{"a b", "c"}}, ""},
+	}
+
+	for _, tt := range tests {
+		v := createFieldValidation(tt.sqlType, tt.col, tt.goType)
+		if !reflect.DeepEqual(v, tt.expected) {
+			t.Errorf("%s %s: expect: %+v, but got %+v", tt.sqlType, tt.col.dbType, tt.expected, v)
+		}
+
+		tag := createValidateAnnotation(v, tt.goType)
+		if tag != tt.tag {
+			t.Errorf("%s %s: expect tag: %s, but got %s", tt.sqlType, tt.col.dbType, tt.tag, tag)
+		}
+	}
+}
@@ -7,9 +7,13 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"math/rand"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	_ "github.com/denisenkom/go-mssqldb"
 	"github.com/droundy/goopt"
@@ -35,6 +39,11 @@ var (
 	modelPackageName = goopt.String([]string{"--model"}, "model", "name to set for model package")
 	daoPackageName   = goopt.String([]string{"--dao"}, "dao", "name to set for dao package")
 	apiPackageName   = goopt.String([]string{"--api"}, "api", "name to set for api package")
+	grpcPackageName  = goopt.String([]string{"--grpc-pkg"}, "grpcapi", "name to set for grpc server package")
+	graphqlPkgName   = goopt.String([]string{"--graphql-pkg"}, "graphqlapi", "name to set for graphql package")
+	clientPkgName    = goopt.String([]string{"--client-pkg"}, "client", "name to set for rest api client package")
+	typescriptDir    = goopt.String([]string{"--typescript-dir"}, "typescript", "output dir of the typescript models and client, relative to the output dir")
+	docsDir          = goopt.String([]string{"--docs-dir"}, "dictionary", "output dir of the data dictionary, relative to the output dir")
 	outDir           = goopt.String([]string{"--out"}, ".", "output dir")
 	module           = goopt.String([]string{"--module"}, "example.com/example", "module path")
 	overwrite        = goopt.Flag([]string{"--overwrite"}, []string{"--no-overwrite"}, "Overwrite existing files (default)", "disable overwriting files")
@@ -48,7 +57,12 @@ var (
 	AddProtobufAnnotation = goopt.Flag([]string{"--protobuf"}, []string{}, "Add protobuf annotations (tags)", "")
 	protoNameFormat       = goopt.String([]string{"--proto-fmt"}, "snake", "proto name format [snake | camel | lower_camel | none]")
 	AddDBAnnotation       = goopt.Flag([]string{"--db"}, []string{}, "Add db annotations (tags)", "")
+	AddValidateAnnotation = goopt.Flag([]string{"--validate"}, []string{}, "Add validate annotations (tags) derived from column constraints", "")
 	UseGureguTypes        = goopt.Flag([]string{"--guregu"}, []string{}, "Add guregu null types", "")
+	versionColumnNames    = goopt.String([]string{"--version-columns"}, "version,lock_version", "comma separated column names used for optimistic locking")
+	softDeleteColumnNames = goopt.String([]string{"--soft-delete-columns"}, "deleted_at", "comma separated column names used to soft delete records")
+	createdAtColumnNames  = goopt.String([]string{"--created-at-columns"}, "created_at,create_time,created_on", "comma separated column names set to the current time when a record is created")
+	updatedAtColumnNames  = goopt.String([]string{"--updated-at-columns"}, "updated_at,update_time,updated_on", "comma separated column names set to the current time when a record is created or updated")
 
 	copyTemplates    = goopt.Flag([]string{"--copy-templates"}, []string{}, "Copy regeneration templates to project directory", "")
 	modGenerate      = goopt.Flag([]string{"--mod"}, []string{}, "Generate go.mod in output dir", "")
@@ -57,9 +71,19 @@ var (
 	daoGenerate      = goopt.Flag([]string{"--generate-dao"}, []string{}, "Generate dao functions", "")
 	projectGenerate  = goopt.Flag([]string{"--generate-proj"}, []string{}, "Generate project readme and gitignore", "")
 	restAPIGenerate  = goopt.Flag([]string{"--rest"}, []string{}, "Enable generating RESTful api", "")
+	routerName       = goopt.String([]string{"--router"}, "gin", "router used by the generated api [gin | httprouter | nethttp | chi | echo]")
+	grpcGenerate     = goopt.Flag([]string{"--grpc"}, []string{}, "Enable generating gRPC services in the protobuf file and a server implementation", "")
+	graphqlGenerate  = goopt.Flag([]string{"--graphql"}, []string{}, "Enable generating a GraphQL schema and resolvers", "")
+	clientGenerate   = goopt.Flag([]string{"--client"}, []string{}, "Enable generating a go client package of the RESTful api", "")
+	tsGenerate       = goopt.Flag([]string{"--typescript"}, []string{}, "Enable generating typescript interfaces of the models and a fetch client of the RESTful api", "")
+	importExport     = goopt.Flag([]string{"--import-export"}, []string{}, "Enable generating csv, json and ndjson export and import handlers of the RESTful api", "")
+	cacheGenerate    = goopt.Flag([]string{"--cache"}, []string{}, "Enable generating caching decorators of the dao reads by primary key and unique keys with an in process lru cache, the server caches with -cache-size and -cache-ttl", "")
+	docsGenerate     = goopt.Flag([]string{"--docs"}, []string{}, "Enable generating a markdown data dictionary of the tables with an entity relationship diagram", "")
+	testsGenerate    = goopt.Flag([]string{"--generate-tests"}, []string{}, "Generate tests of the dao and rest api run against an in memory sqlite db or TEST_DB_DRIVER/TEST_DB_DSN", "")
 
 	serverHost          = goopt.String([]string{"--host"}, "localhost", "host for server")
 	serverPort          = goopt.Int([]string{"--port"}, 8080, "port for server")
+	grpcPort            = goopt.Int([]string{"--grpc-port"}, 9090, "port for grpc server")
 	swaggerVersion      = goopt.String([]string{"--swagger_version"}, "1.0", "swagger version")
 	swaggerBasePath     = goopt.String([]string{"--swagger_path"}, "/", "swagger base path")
 	swaggerTos          = goopt.String([]string{"--swagger_tos"}, "", "swagger tos url")
@@ -67,6 +91,21 @@ var (
 	swaggerContactURL   = goopt.String([]string{"--swagger_contact_url"}, "http://me.com/terms.html", "swagger contact url")
 	swaggerContactEmail = goopt.String([]string{"--swagger_contact_email"}, "me@me.com", "swagger contact email")
 
+	snapshotFileName = goopt.String([]string{"--snapshot"}, "schema.json", "schema snapshot file (json) written by migrate snapshot and compared by migrate diff")
+	fromSQLType      = goopt.String([]string{"--from-sqltype"}, "", "sql database type of the database migrate diff compares against instead of the snapshot, --sqltype by default")
+	fromSQLConnStr   = goopt.String([]string{"--from-connstr"}, "", "connection string of the database migrate diff compares against instead of the snapshot")
+	migrationsDir    = goopt.String([]string{"--migrations-dir"}, "migrations", "output dir of the migrations written by migrate diff, relative to the output dir")
+	migrateFormat    = goopt.String([]string{"--migrate-format"}, "golang-migrate", "migration file format [golang-migrate | goose]")
+	migrationName    = goopt.String([]string{"--migration-name"}, "schema", "name of the migration written by migrate diff")
+	ddlFileName      = goopt.String([]string{"--ddl-file"}, "", "file the ddl command writes the create table statements to, stdout by default")
+	diagramFormat    = goopt.String([]string{"--format"}, "mermaid", "diagram format [mermaid | plantuml | dot]")
+	diagramHops      = goopt.Int([]string{"--hops"}, 0, "number of foreign key hops of neighbouring tables the diagram command adds to the tables listed")
+	diagramFileName  = goopt.String([]string{"--diagram-file"}, "", "file the diagram command writes the diagram to, stdout by default")
+	seedRows         = goopt.Int([]string{"--rows"}, 10, "number of fake rows the seed command generates per table")
+	seedFormat       = goopt.String([]string{"--seed-format"}, "db", "seed output [db | sql | json | yaml], db inserts the rows into the database")
+	seedFileName     = goopt.String([]string{"--seed-file"}, "seed.sql", "file the seed command writes the sql inserts to, relative to the output dir")
+	fixturesDir      = goopt.String([]string{"--fixtures-dir"}, "fixtures", "output dir of the json or yaml fixtures written by the seed command, relative to the output dir")
+
 	verbose = goopt.Flag([]string{"-v", "--verbose"}, []string{}, "Enable verbose output", "")
 
 	baseTemplates *packr.Box
@@ -84,6 +123,29 @@ func init() {
 
            sqltype - sql database type such as [ mysql, mssql, postgres, sqlite, etc. ]
 
+       gen --sqltype=mysql --connstr "user:password@/dbname" --database <databaseName> migrate snapshot [--snapshot=schema.json]
+       gen --sqltype=mysql --connstr "user:password@/dbname" --database <databaseName> migrate diff [--snapshot=schema.json | --from-connstr=s] [--migrate-format=goose]
+
+           migrate snapshot - save the schema of the database to the snapshot file
+           migrate diff     - write up/down migrations from the snapshot (or the --from-connstr database) to the schema of the database
+
+       gen --sqltype=postgres ddl <model package dir> [--ddl-file=schema.sql]
+
+           ddl - write the create table statements of the structs of a go package, reading db, gorm, sql and ddl tags
+
+       gen --sqltype=mysql --connstr "user:password@/dbname" --database <databaseName> diagram [table...] [--hops=1] [--format=plantuml] [--diagram-file=schema.puml]
+
+           diagram - write an entity relationship diagram of the tables, or of the tables listed and their neighbours within --hops foreign keys
+
+       gen --sqltype=mysql --connstr "user:password@/dbname" --database <databaseName> seed [--rows=10] [--seed-format=sql | json | yaml]
+
+           seed - insert fake rows into the tables in foreign key order, or write them as sql inserts or json or yaml fixtures
+
+       gen --sqltype=mysql --connstr "user:password@/dbname" --database <databaseName> [generate flags] check
+
+           check - render the code from the schema of the database and compare it with the code generated in --out,
+                   exits non-zero listing the changed tables, columns and files. Run it with the flags the code was generated with.
+
 `
 
 	//Parse options
@@ -140,6 +202,11 @@ func main() {
 		return
 	}
 
+	if len(goopt.Args) > 0 && goopt.Args[0] == "ddl" {
+		generateStructDDL(goopt.Args[1:])
+		return
+	}
+
 	// Username is required
 	if sqlConnStr == nil || *sqlConnStr == "" || *sqlConnStr == "nil" {
 		fmt.Printf("sql connection string is required! Add it with --connstr=s\n\n")
@@ -153,6 +220,12 @@ func main() {
 		return
 	}
 
+	if _, err := dbmeta.LookupRouter(strings.ToLower(*routerName)); err != nil {
+		fmt.Printf("%v\n\n", err)
+		fmt.Println(goopt.Usage())
+		return
+	}
+
 	db, err := initializeDB()
 	if err != nil {
 		return
@@ -172,9 +245,32 @@ func main() {
 		}
 	}
 
-	fmt.Printf("Generating code for the following tables (%d)\n", len(dbTables))
-	for i, tableName := range dbTables {
-		fmt.Printf("[%d] %s\n", i, tableName)
+	checkMode := false
+	seedMode := false
+	if len(goopt.Args) > 0 {
+		switch goopt.Args[0] {
+		case "migrate":
+			migrate(db, dbTables, goopt.Args[1:])
+			return
+		case "diagram":
+			diagram(db, dbTables, goopt.Args[1:])
+			return
+		case "check":
+			checkMode = true
+		case "seed":
+			seedMode = true
+		default:
+			fmt.Printf("unknown command: %s\n\n", goopt.Args[0])
+			fmt.Println(goopt.Usage())
+			return
+		}
+	}
+
+	if !seedMode {
+		fmt.Printf("Generating code for the following tables (%d)\n", len(dbTables))
+		for i, tableName := range dbTables {
+			fmt.Printf("[%d] %s\n", i, tableName)
+		}
 	}
 
 	conf := dbmeta.NewConfig(LoadTemplate)
@@ -198,6 +294,30 @@ func main() {
 		loadContextMapping(conf)
 	}
 
+	if *AddProtobufAnnotation || *grpcGenerate {
+		conf.ProtobufLock, err = dbmeta.LoadProtobufLock(protobufLockFileName())
+		if err != nil {
+			fmt.Printf("Error loading protobuf lock file error: %v\n", err)
+			return
+		}
+	}
+
+	var manifest *dbmeta.Manifest
+	if checkMode {
+		manifest, err = dbmeta.LoadManifest(filepath.Join(*outDir, dbmeta.ManifestFileName))
+		if err != nil {
+			fmt.Printf("Error loading manifest, generate the code before checking it error: %v\n", err)
+			db.Close()
+			os.Exit(1)
+		}
+
+		// the files are rendered with the command line and connection string recorded in their headers
+		conf.Check = true
+		conf.CmdLine = manifest.CommandLine
+		conf.SqlConnStr = manifest.SQLConnStr
+		*sqlConnStr = manifest.SQLConnStr
+	}
+
 	tableInfos = dbmeta.LoadTableInfo(db, dbTables, conf)
 	conf.ContextMap["tableInfos"] = tableInfos
 
@@ -206,7 +326,102 @@ func main() {
 		return
 	}
 
+	if seedMode {
+		seed(db, dbTables)
+		return
+	}
+
 	generate(conf)
+
+	current, err := dbmeta.LoadSchemaSnapshot(db, *sqlType, *sqlDatabase, dbTables)
+	if err != nil {
+		fmt.Printf("Error loading schema error: %v\n", err)
+		return
+	}
+
+	if *docsGenerate {
+		if err = generateDocs(conf, current); err != nil {
+			return
+		}
+	}
+
+	if checkMode {
+		if !check(conf, manifest, current) {
+			db.Close()
+			os.Exit(1)
+		}
+		return
+	}
+
+	manifest = &dbmeta.Manifest{CommandLine: conf.CmdLine, SQLConnStr: *sqlConnStr, Schema: current, Files: outputFiles(conf)}
+	if err = manifest.Save(filepath.Join(*outDir, dbmeta.ManifestFileName)); err != nil {
+		fmt.Printf("Error writing manifest error: %v\n", err)
+	}
+}
+
+// outputFiles the files written by generate relative to the output dir
+func outputFiles(conf *dbmeta.Config) []string {
+	seen := make(map[string]bool)
+	var files []string
+	for _, f := range conf.OutputFiles {
+		if rel, err := filepath.Rel(*outDir, f); err == nil {
+			f = filepath.ToSlash(rel)
+		}
+		if !seen[f] {
+			seen[f] = true
+			files = append(files, f)
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+// check reports the schema changes since the manifest was written and the generated files that differ from the files
+// rendered from the current schema, returning false when anything differs
+func check(conf *dbmeta.Config, manifest *dbmeta.Manifest, current *dbmeta.SchemaSnapshot) bool {
+	changes := manifest.Schema.Changes(current)
+
+	var stale []string
+	for _, f := range conf.StaleFiles {
+		name := f.Name
+		if rel, err := filepath.Rel(*outDir, name); err == nil {
+			name = filepath.ToSlash(rel)
+		}
+		stale = append(stale, fmt.Sprintf("%s %s", name, f.Reason))
+	}
+
+	generated := make(map[string]bool)
+	for _, f := range outputFiles(conf) {
+		generated[f] = true
+	}
+	for _, f := range manifest.Files {
+		if !generated[f] {
+			stale = append(stale, fmt.Sprintf("%s no longer generated", f))
+		}
+	}
+	sort.Strings(stale)
+
+	if len(changes) == 0 && len(stale) == 0 {
+		fmt.Printf("generated code in %s is up to date\n", *outDir)
+		return true
+	}
+
+	if len(changes) > 0 {
+		fmt.Printf("schema changes since the code was generated (%d)\n", len(changes))
+		for _, c := range changes {
+			fmt.Printf("    %s\n", c)
+		}
+	}
+
+	if len(stale) > 0 {
+		fmt.Printf("generated files out of date (%d)\n", len(stale))
+		for _, f := range stale {
+			fmt.Printf("    %s\n", f)
+		}
+	}
+
+	fmt.Printf("\nregenerate the code with\n    %s\n", manifest.CommandLine)
+	return false
 }
 
 func initializeDB() (db *sql.DB, err error) {
@@ -241,18 +456,42 @@ func initialize(conf *dbmeta.Config) {
 	if apiPackageName == nil || *apiPackageName == "" {
 		*apiPackageName = "api"
 	}
+	if grpcPackageName == nil || *grpcPackageName == "" {
+		*grpcPackageName = "grpcapi"
+	}
+	if graphqlPkgName == nil || *graphqlPkgName == "" {
+		*graphqlPkgName = "graphqlapi"
+	}
+	if clientPkgName == nil || *clientPkgName == "" {
+		*clientPkgName = "client"
+	}
 
 	conf.SqlType = *sqlType
 	conf.SqlDatabase = *sqlDatabase
 	conf.ModelPackageName = *modelPackageName
 	conf.DaoPackageName = *daoPackageName
 	conf.ApiPackageName = *apiPackageName
+	conf.GrpcPackageName = *grpcPackageName
+	conf.GenerateGrpc = *grpcGenerate
+	conf.GraphqlPackageName = *graphqlPkgName
+	conf.GenerateGraphql = *graphqlGenerate
+	conf.GenerateTests = *testsGenerate
+	conf.GenerateClient = *clientGenerate
+	conf.GenerateImportExport = *importExport
+	conf.GenerateCache = *cacheGenerate
+	conf.ClientPackageName = *clientPkgName
 
 	conf.AddJSONAnnotation = *AddJSONAnnotation
 	conf.AddGormAnnotation = *AddGormAnnotation
 	conf.AddProtobufAnnotation = *AddProtobufAnnotation
 	conf.AddDBAnnotation = *AddDBAnnotation
+	conf.AddValidateAnnotation = *AddValidateAnnotation
 	conf.UseGureguTypes = *UseGureguTypes
+	conf.VersionColumnNames = strings.Split(*versionColumnNames, ",")
+	conf.SoftDeleteColumnNames = strings.Split(*softDeleteColumnNames, ",")
+	conf.CreatedAtColumnNames = strings.Split(*createdAtColumnNames, ",")
+	conf.UpdatedAtColumnNames = strings.Split(*updatedAtColumnNames, ",")
+	conf.Router = strings.ToLower(*routerName)
 	conf.JsonNameFormat = *jsonNameFormat
 	conf.ProtobufNameFormat = *protoNameFormat
 	conf.Verbose = *verbose
@@ -262,12 +501,16 @@ func initialize(conf *dbmeta.Config) {
 	conf.SqlConnStr = *sqlConnStr
 	conf.ServerPort = *serverPort
 	conf.ServerHost = *serverHost
+	conf.GrpcPort = *grpcPort
 	conf.Overwrite = *overwrite
 
 	conf.Module = *module
 	conf.ModelFQPN = *module + "/" + *modelPackageName
 	conf.DaoFQPN = *module + "/" + *daoPackageName
 	conf.ApiFQPN = *module + "/" + *apiPackageName
+	conf.GrpcFQPN = *module + "/" + *grpcPackageName
+	conf.GraphqlFQPN = *module + "/" + *graphqlPkgName
+	conf.ClientFQPN = *module + "/" + *clientPkgName
 
 	conf.Swagger.Version = *swaggerVersion
 	conf.Swagger.BasePath = *swaggerBasePath
@@ -308,6 +551,276 @@ func executeCustomScript(conf *dbmeta.Config) {
 	execTemplate(conf, "exec", content, data)
 }
 
+// generateStructDDL writes the create table statements of the structs of the go package in the dir given by args
+func generateStructDDL(args []string) {
+	if len(args) != 1 {
+		fmt.Printf("ddl requires the dir of a go package\n\n")
+		fmt.Println(goopt.Usage())
+		return
+	}
+
+	structSchema, err := dbmeta.LoadStructSchema(args[0], *sqlType)
+	if err != nil {
+		fmt.Printf("Error loading structs error: %v\n", err)
+		return
+	}
+
+	if len(structSchema.Tables) == 0 {
+		fmt.Printf("No structs with a TableName method or db, gorm, sql or ddl tags found in %s\n", args[0])
+		return
+	}
+
+	ddl := dbmeta.CreateSchemaDDL(structSchema)
+	if *ddlFileName == "" {
+		fmt.Print(ddl)
+		return
+	}
+
+	err = ioutil.WriteFile(*ddlFileName, []byte(ddl), 0644)
+	if err != nil {
+		fmt.Printf("Error writing %s error: %v\n", *ddlFileName, err)
+		return
+	}
+	fmt.Printf("writing %s\n", *ddlFileName)
+}
+
+// migrate runs the migrate snapshot and migrate diff commands
+func migrate(db *sql.DB, dbTables []string, args []string) {
+	if len(args) != 1 || (args[0] != "snapshot" && args[0] != "diff") {
+		fmt.Printf("migrate requires a command [ snapshot | diff ]\n\n")
+		fmt.Println(goopt.Usage())
+		return
+	}
+
+	current, err := dbmeta.LoadSchemaSnapshot(db, *sqlType, *sqlDatabase, dbTables)
+	if err != nil {
+		fmt.Printf("Error loading schema error: %v\n", err)
+		return
+	}
+
+	if args[0] == "snapshot" {
+		err = current.Save(*snapshotFileName)
+		if err != nil {
+			fmt.Printf("Error saving schema snapshot %s error: %v\n", *snapshotFileName, err)
+			return
+		}
+		fmt.Printf("Saved schema snapshot of %d tables to %s\n", len(current.Tables), *snapshotFileName)
+		return
+	}
+
+	from, err := loadMigrationSource(dbTables)
+	if err != nil {
+		fmt.Printf("Error loading schema to diff against error: %v\n", err)
+		return
+	}
+
+	migration, err := dbmeta.DiffSchemaSnapshots(from, current)
+	if err != nil {
+		fmt.Printf("Error diffing schema error: %v\n", err)
+		return
+	}
+
+	if migration.Empty() {
+		fmt.Printf("No schema changes found\n")
+		return
+	}
+
+	files, err := migration.Files(*migrateFormat, time.Now().UTC().Format("20060102150405"), *migrationName)
+	if err != nil {
+		fmt.Printf("%v\n", err)
+		return
+	}
+
+	dir := filepath.Join(*outDir, *migrationsDir)
+	err = os.MkdirAll(dir, 0777)
+	if err != nil {
+		fmt.Printf("unable to create migrations dir %s error: %v\n", dir, err)
+		return
+	}
+
+	for _, file := range files {
+		outputFile := filepath.Join(dir, file.Name)
+		err = ioutil.WriteFile(outputFile, []byte(file.Content), 0644)
+		if err != nil {
+			fmt.Printf("Error writing %s error: %v\n", outputFile, err)
+			return
+		}
+		fmt.Printf("writing %s\n", outputFile)
+	}
+
+	if *fromSQLConnStr == "" {
+		fmt.Printf("Run migrate snapshot once the migration is applied to compare the next migration against the new schema\n")
+	}
+}
+
+// loadMigrationSource loads the schema migrate diff starts from, the --from-connstr database or the snapshot file limited to
+// the tables being compared when --table is set
+func diagram(db *sql.DB, dbTables []string, args []string) {
+	current, err := dbmeta.LoadSchemaSnapshot(db, *sqlType, *sqlDatabase, dbTables)
+	if err != nil {
+		fmt.Printf("Error loading schema error: %v\n", err)
+		return
+	}
+
+	if len(args) > 0 {
+		current, err = current.Neighbours(args, *diagramHops)
+		if err != nil {
+			fmt.Printf("Error selecting diagram tables error: %v\n", err)
+			return
+		}
+	}
+
+	content, err := dbmeta.RenderERDiagram(current, *diagramFormat)
+	if err != nil {
+		fmt.Printf("Error rendering diagram error: %v\n", err)
+		return
+	}
+
+	if *diagramFileName == "" {
+		fmt.Print(content)
+		return
+	}
+
+	err = ioutil.WriteFile(*diagramFileName, []byte(content), 0644)
+	if err != nil {
+		fmt.Printf("Error writing %s error: %v\n", *diagramFileName, err)
+		return
+	}
+	fmt.Printf("writing %s\n", *diagramFileName)
+}
+
+func seed(db *sql.DB, dbTables []string) {
+	current, err := dbmeta.LoadSchemaSnapshot(db, *sqlType, *sqlDatabase, dbTables)
+	if err != nil {
+		fmt.Printf("Error loading schema error: %v\n", err)
+		return
+	}
+
+	format := strings.ToLower(*seedFormat)
+	switch format {
+	case "db", "sql", "json", "yaml":
+	default:
+		fmt.Printf("unsupported seed format: %s\n\n", *seedFormat)
+		fmt.Println(goopt.Usage())
+		return
+	}
+
+	if format == "json" || format == "yaml" {
+		err = os.MkdirAll(filepath.Join(*outDir, *fixturesDir), 0777)
+		if err != nil {
+			fmt.Printf("unable to create fixtures dir: %s error: %v\n", *fixturesDir, err)
+			return
+		}
+	}
+
+	// the samples of the models are generated from fixed seeds, the rows seeded differ each run
+	rand.Seed(time.Now().UnixNano())
+
+	// rows written to files keep the values of auto increment columns, the foreign keys of the rows reference them
+	seeder := dbmeta.NewSeeder(*seedRows, format != "db")
+
+	var statements []string
+	for _, t := range dbmeta.SeedOrder(current) {
+		modelInfo, ok := tableInfos[t.Name]
+		if !ok {
+			continue
+		}
+
+		if format == "db" {
+			if err = seeder.LoadKeys(db, *sqlType, t); err != nil {
+				fmt.Printf("Error seeding %s error: %v\n", t.Name, err)
+				return
+			}
+		}
+
+		table, err := seeder.GenerateTable(t, modelInfo)
+		if err != nil {
+			fmt.Printf("Error seeding %s error: %v\n", t.Name, err)
+			return
+		}
+
+		switch format {
+		case "db":
+			if err = table.Insert(db, *sqlType); err != nil {
+				fmt.Printf("Error seeding %s error: %v\n", t.Name, err)
+				return
+			}
+			fmt.Printf("inserted %d rows into %s\n", len(table.Rows), t.Name)
+		case "sql":
+			statements = append(statements, table.InsertStatements(*sqlType)...)
+		default:
+			var content []byte
+			if format == "json" {
+				content, err = table.FixtureJSON()
+			} else {
+				content, err = table.FixtureYAML()
+			}
+			if err != nil {
+				fmt.Printf("Error seeding %s error: %v\n", t.Name, err)
+				return
+			}
+
+			fixtureFile := filepath.Join(*outDir, *fixturesDir, fmt.Sprintf("%s.%s", t.Name, format))
+			if err = ioutil.WriteFile(fixtureFile, content, 0644); err != nil {
+				fmt.Printf("Error writing %s error: %v\n", fixtureFile, err)
+				return
+			}
+			fmt.Printf("writing %s\n", fixtureFile)
+		}
+	}
+
+	if format == "sql" {
+		sqlFile := filepath.Join(*outDir, *seedFileName)
+		if err = ioutil.WriteFile(sqlFile, []byte(strings.Join(statements, "\n")+"\n"), 0644); err != nil {
+			fmt.Printf("Error writing %s error: %v\n", sqlFile, err)
+			return
+		}
+		fmt.Printf("writing %s\n", sqlFile)
+	}
+}
+
+func loadMigrationSource(dbTables []string) (*dbmeta.SchemaSnapshot, error) {
+	if *fromSQLConnStr == "" {
+		snapshot, err := dbmeta.LoadSchemaSnapshotFile(*snapshotFileName)
+		if err != nil {
+			return nil, err
+		}
+
+		if *sqlTable != "" {
+			tables := snapshot.Tables
+			snapshot.Tables = nil
+			for _, t := range tables {
+				for _, tableName := range dbTables {
+					if t.Name == tableName {
+						snapshot.Tables = append(snapshot.Tables, t)
+					}
+				}
+			}
+		}
+		return snapshot, nil
+	}
+
+	fromType := *fromSQLType
+	if fromType == "" {
+		fromType = *sqlType
+	}
+
+	db, err := sql.Open(fromType, *fromSQLConnStr)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	fromTables := dbTables
+	if *sqlTable == "" {
+		fromTables, err = schema.TableNames(db)
+		if err != nil {
+			return nil, fmt.Errorf("unable to fetch tables from %s: %v", *fromSQLConnStr, err)
+		}
+	}
+	return dbmeta.LoadSchemaSnapshot(db, fromType, *sqlDatabase, fromTables)
+}
+
 func execTemplate(conf *dbmeta.Config, name, templateStr string, data map[string]interface{}) {
 
 	data["DatabaseName"] = *sqlDatabase
@@ -349,6 +862,9 @@ func generate(conf *dbmeta.Config) {
 	modelDir := filepath.Join(*outDir, *modelPackageName)
 	apiDir := filepath.Join(*outDir, *apiPackageName)
 	daoDir := filepath.Join(*outDir, *daoPackageName)
+	grpcDir := filepath.Join(*outDir, *grpcPackageName)
+	graphqlDir := filepath.Join(*outDir, *graphqlPkgName)
+	clientDir := filepath.Join(*outDir, *clientPkgName)
 
 	err = os.MkdirAll(*outDir, 0777)
 	if err != nil && !*overwrite {
@@ -377,6 +893,28 @@ func generate(conf *dbmeta.Config) {
 			return
 		}
 	}
+	if *grpcGenerate {
+		err = os.MkdirAll(grpcDir, 0777)
+		if err != nil && !*overwrite {
+			fmt.Printf("unable to create grpcDir: %s error: %v\n", grpcDir, err)
+			return
+		}
+	}
+	if *graphqlGenerate {
+		err = os.MkdirAll(graphqlDir, 0777)
+		if err != nil && !*overwrite {
+			fmt.Printf("unable to create graphqlDir: %s error: %v\n", graphqlDir, err)
+			return
+		}
+	}
+	if *clientGenerate {
+		err = os.MkdirAll(clientDir, 0777)
+		if err != nil && !*overwrite {
+			fmt.Printf("unable to create clientDir: %s error: %v\n", clientDir, err)
+			return
+		}
+	}
+
 	var ModelTmpl string
 	var ModelBaseTmpl string
 	var ControllerTmpl string
@@ -384,7 +922,14 @@ func generate(conf *dbmeta.Config) {
 	var DaoFileName string
 
 	var DaoInitTmpl string
+	var DaoErrorsTmpl string
+	var DaoCacheTmpl string
 	var GoModuleTmpl string
+	var GrpcTmpl string
+	var GraphqlTmpl string
+	var ClientTmpl string
+	var DaoTestTmpl string
+	var APITestTmpl string
 
 	if ControllerTmpl, err = LoadTemplate("api.go.tmpl"); err != nil {
 		fmt.Printf("Error loading template %v\n", err)
@@ -413,11 +958,49 @@ func generate(conf *dbmeta.Config) {
 		}
 	}
 
+	if DaoErrorsTmpl, err = LoadTemplate("dao_errors.go.tmpl"); err != nil {
+		fmt.Printf("Error loading template %v\n", err)
+		return
+	}
+
+	if *cacheGenerate {
+		if DaoCacheTmpl, err = LoadTemplate("dao_cache.go.tmpl"); err != nil {
+			fmt.Printf("Error loading template %v\n", err)
+			return
+		}
+	}
+
 	if GoModuleTmpl, err = LoadTemplate("gomod.tmpl"); err != nil {
 		fmt.Printf("Error loading template %v\n", err)
 		return
 	}
 
+	if GrpcTmpl, err = LoadTemplate("grpc.go.tmpl"); err != nil {
+		fmt.Printf("Error loading template %v\n", err)
+		return
+	}
+
+	if GraphqlTmpl, err = LoadTemplate("graphql.go.tmpl"); err != nil {
+		fmt.Printf("Error loading template %v\n", err)
+		return
+	}
+
+	if ClientTmpl, err = LoadTemplate("client.go.tmpl"); err != nil {
+		fmt.Printf("Error loading template %v\n", err)
+		return
+	}
+
+	if *testsGenerate {
+		if DaoTestTmpl, err = LoadTemplate("dao_test.go.tmpl"); err != nil {
+			fmt.Printf("Error loading template %v\n", err)
+			return
+		}
+		if APITestTmpl, err = LoadTemplate("api_test.go.tmpl"); err != nil {
+			fmt.Printf("Error loading template %v\n", err)
+			return
+		}
+	}
+
 	if ModelTmpl, err = LoadTemplate("model.go.tmpl"); err != nil {
 		fmt.Printf("Error loading template %v\n", err)
 		return
@@ -455,6 +1038,31 @@ func generate(conf *dbmeta.Config) {
 			outputFile := filepath.Join(daoDir, CreateGoSrcFileName(tableName))
 			conf.WriteTemplate(DaoFileName, DaoTmpl, modelInfo, outputFile, true)
 		}
+
+		if *grpcGenerate {
+			grpcFile := filepath.Join(grpcDir, CreateGoSrcFileName(tableName))
+			conf.WriteTemplate("grpc.go.tmpl", GrpcTmpl, modelInfo, grpcFile, true)
+		}
+
+		if *graphqlGenerate {
+			graphqlFile := filepath.Join(graphqlDir, CreateGoSrcFileName(tableName))
+			conf.WriteTemplate("graphql.go.tmpl", GraphqlTmpl, modelInfo, graphqlFile, true)
+		}
+
+		if *clientGenerate {
+			clientFile := filepath.Join(clientDir, CreateGoSrcFileName(tableName))
+			conf.WriteTemplate("client.go.tmpl", ClientTmpl, modelInfo, clientFile, true)
+		}
+
+		if *testsGenerate && *daoGenerate {
+			daoTestFile := filepath.Join(daoDir, CreateGoTestFileName(tableName))
+			conf.WriteTemplate("dao_test.go.tmpl", DaoTestTmpl, modelInfo, daoTestFile, true)
+
+			if *restAPIGenerate {
+				apiTestFile := filepath.Join(apiDir, CreateGoTestFileName(tableName))
+				conf.WriteTemplate("api_test.go.tmpl", APITestTmpl, modelInfo, apiTestFile, true)
+			}
+		}
 	}
 
 	data := map[string]interface{}{}
@@ -463,10 +1071,19 @@ func generate(conf *dbmeta.Config) {
 		if err = generateRestBaseFiles(conf, apiDir); err != nil {
 			return
 		}
+
+		if err = generateOpenAPIFiles(conf); err != nil {
+			return
+		}
 	}
 
 	if *daoGenerate {
 		conf.WriteTemplate("daoBase", DaoInitTmpl, data, filepath.Join(daoDir, "dao_base.go"), true)
+		conf.WriteTemplate("daoErrors", DaoErrorsTmpl, data, filepath.Join(daoDir, "dao_errors.go"), true)
+
+		if *cacheGenerate {
+			conf.WriteTemplate("daoCache", DaoCacheTmpl, data, filepath.Join(daoDir, "dao_cache.go"), true)
+		}
 	}
 
 	conf.WriteTemplate("modelBase", ModelBaseTmpl, data, filepath.Join(modelDir, "model_base.go"), true)
@@ -481,12 +1098,42 @@ func generate(conf *dbmeta.Config) {
 		}
 	}
 
-	if *AddProtobufAnnotation {
+	if *AddProtobufAnnotation || *grpcGenerate {
 		if err = generateProtobufDefinitionFile(conf, data); err != nil {
 			return
 		}
 	}
 
+	if *grpcGenerate {
+		if err = generateGrpcBaseFiles(conf, grpcDir); err != nil {
+			return
+		}
+	}
+
+	if *graphqlGenerate {
+		if err = generateGraphqlBaseFiles(conf, graphqlDir); err != nil {
+			return
+		}
+	}
+
+	if *clientGenerate {
+		if err = generateClientBaseFiles(conf, clientDir); err != nil {
+			return
+		}
+	}
+
+	if *tsGenerate {
+		if err = generateTypescriptFiles(conf); err != nil {
+			return
+		}
+	}
+
+	if *testsGenerate && *daoGenerate {
+		if err = generateTestBaseFiles(conf, daoDir, apiDir); err != nil {
+			return
+		}
+	}
+
 	data = map[string]interface{}{
 		"deps":        "go list -f '{{ join .Deps  \"\\n\"}}' .",
 		"CommandLine": conf.CmdLine,
@@ -504,7 +1151,7 @@ func generate(conf *dbmeta.Config) {
 		}
 	}
 
-	if *copyTemplates {
+	if *copyTemplates && !conf.Check {
 		if err = copyTemplatesToTarget(); err != nil {
 			return
 		}
@@ -528,6 +1175,194 @@ func generateRestBaseFiles(conf *dbmeta.Config, apiDir string) (err error) {
 
 	conf.WriteTemplate("router", RouterTmpl, data, filepath.Join(apiDir, "router.go"), true)
 	conf.WriteTemplate("example server", HTTPUtilsTmpl, data, filepath.Join(apiDir, "http_utils.go"), true)
+
+	if *importExport {
+		var ImportExportTmpl string
+		if ImportExportTmpl, err = LoadTemplate("import_export.go.tmpl"); err != nil {
+			fmt.Printf("Error loading template %v\n", err)
+			return
+		}
+		conf.WriteTemplate("import export", ImportExportTmpl, data, filepath.Join(apiDir, "import_export.go"), true)
+	}
+	return nil
+}
+
+func generateGraphqlBaseFiles(conf *dbmeta.Config, graphqlDir string) (err error) {
+	var GraphqlSchemaTmpl string
+	var GraphqlBaseTmpl string
+
+	if GraphqlSchemaTmpl, err = LoadTemplate("graphql_schema.tmpl"); err != nil {
+		fmt.Printf("Error loading template %v\n", err)
+		return
+	}
+	if GraphqlBaseTmpl, err = LoadTemplate("graphql_base.go.tmpl"); err != nil {
+		fmt.Printf("Error loading template %v\n", err)
+		return
+	}
+
+	schemaFile := filepath.Join(graphqlDir, "schema.graphql")
+	conf.WriteTemplate("graphql schema", GraphqlSchemaTmpl, map[string]interface{}{}, schemaFile, false)
+
+	// the schema is embedded in the package as written, including a schema.graphql that was not overwritten
+	schema, err := ioutil.ReadFile(schemaFile)
+	if err != nil {
+		fmt.Printf("unable to read graphql schema: %s error: %v\n", schemaFile, err)
+		return
+	}
+
+	data := map[string]interface{}{"graphqlSchema": string(schema)}
+	conf.WriteTemplate("graphql base", GraphqlBaseTmpl, data, filepath.Join(graphqlDir, "graphql_base.go"), true)
+	return nil
+}
+
+func generateClientBaseFiles(conf *dbmeta.Config, clientDir string) (err error) {
+	var ClientBaseTmpl string
+
+	if ClientBaseTmpl, err = LoadTemplate("client_base.go.tmpl"); err != nil {
+		fmt.Printf("Error loading template %v\n", err)
+		return
+	}
+
+	data := map[string]interface{}{}
+	conf.WriteTemplate("client base", ClientBaseTmpl, data, filepath.Join(clientDir, "client.go"), true)
+	return nil
+}
+
+func generateTypescriptFiles(conf *dbmeta.Config) (err error) {
+	var TypescriptModelsTmpl string
+	var TypescriptClientTmpl string
+
+	if TypescriptModelsTmpl, err = LoadTemplate("typescript_models.ts.tmpl"); err != nil {
+		fmt.Printf("Error loading template %v\n", err)
+		return
+	}
+	if TypescriptClientTmpl, err = LoadTemplate("typescript_client.ts.tmpl"); err != nil {
+		fmt.Printf("Error loading template %v\n", err)
+		return
+	}
+
+	tsDir := filepath.Join(*outDir, *typescriptDir)
+	err = os.MkdirAll(tsDir, 0777)
+	if err != nil && !*overwrite {
+		fmt.Printf("unable to create typescript dir: %s error: %v\n", tsDir, err)
+		return
+	}
+
+	data := map[string]interface{}{}
+	conf.WriteTemplate("typescript models", TypescriptModelsTmpl, data, filepath.Join(tsDir, "models.ts"), false)
+	conf.WriteTemplate("typescript client", TypescriptClientTmpl, data, filepath.Join(tsDir, "client.ts"), false)
+	return nil
+}
+
+func generateDocs(conf *dbmeta.Config, current *dbmeta.SchemaSnapshot) (err error) {
+	var DocsIndexTmpl string
+	var DocsTableTmpl string
+
+	if DocsIndexTmpl, err = LoadTemplate("docs_index.md.tmpl"); err != nil {
+		fmt.Printf("Error loading template %v\n", err)
+		return
+	}
+	if DocsTableTmpl, err = LoadTemplate("docs_table.md.tmpl"); err != nil {
+		fmt.Printf("Error loading template %v\n", err)
+		return
+	}
+
+	dir := filepath.Join(*outDir, *docsDir)
+	err = os.MkdirAll(dir, 0777)
+	if err != nil && !*overwrite {
+		fmt.Printf("unable to create docs dir: %s error: %v\n", dir, err)
+		return
+	}
+
+	tables := dbmeta.NewDocsTables(current)
+	for _, table := range tables {
+		data := map[string]interface{}{"table": table}
+		conf.WriteTemplate("docs table", DocsTableTmpl, data, filepath.Join(dir, table.FileName), false)
+	}
+
+	diagram, err := dbmeta.RenderERDiagram(current, "mermaid")
+	if err != nil {
+		fmt.Printf("Error rendering diagram %v\n", err)
+		return
+	}
+
+	data := map[string]interface{}{
+		"tables":  tables,
+		"diagram": diagram,
+	}
+	conf.WriteTemplate("docs index", DocsIndexTmpl, data, filepath.Join(dir, "README.md"), false)
+	return nil
+}
+
+func generateTestBaseFiles(conf *dbmeta.Config, daoDir, apiDir string) (err error) {
+	var TestMainTmpl string
+
+	if TestMainTmpl, err = LoadTemplate("test_main.go.tmpl"); err != nil {
+		fmt.Printf("Error loading template %v\n", err)
+		return
+	}
+
+	data := map[string]interface{}{"testPackageName": *daoPackageName}
+	conf.WriteTemplate("dao test main", TestMainTmpl, data, filepath.Join(daoDir, "main_test.go"), true)
+
+	if *restAPIGenerate {
+		data = map[string]interface{}{"testPackageName": *apiPackageName}
+		conf.WriteTemplate("api test main", TestMainTmpl, data, filepath.Join(apiDir, "main_test.go"), true)
+	}
+	return nil
+}
+
+func generateGrpcBaseFiles(conf *dbmeta.Config, grpcDir string) (err error) {
+	var GrpcBaseTmpl string
+
+	if GrpcBaseTmpl, err = LoadTemplate("grpc_base.go.tmpl"); err != nil {
+		fmt.Printf("Error loading template %v\n", err)
+		return
+	}
+
+	data := map[string]interface{}{}
+	conf.WriteTemplate("grpc base", GrpcBaseTmpl, data, filepath.Join(grpcDir, "grpc_base.go"), true)
+	return nil
+}
+
+func generateOpenAPIFiles(conf *dbmeta.Config) (err error) {
+	var OpenAPITmpl string
+
+	if OpenAPITmpl, err = LoadTemplate("openapi.go.tmpl"); err != nil {
+		fmt.Printf("Error loading template %v\n", err)
+		return
+	}
+
+	spec, err := conf.GenerateOpenAPI(tableInfos)
+	if err != nil {
+		fmt.Printf("Error generating openapi spec %v\n", err)
+		return
+	}
+
+	specFile := filepath.Join(*outDir, "openapi.yaml")
+	if *overwrite || conf.Check || !dbmeta.Exists(specFile) {
+		if err = conf.WriteOutput(specFile, spec, 0666); err != nil {
+			fmt.Printf("error writing %s - error: %v\n", specFile, err)
+			return
+		}
+	}
+
+	docsDir := filepath.Join(*outDir, "docs")
+	err = os.MkdirAll(docsDir, 0777)
+	if err != nil {
+		fmt.Printf("unable to create docsDir: %s error: %v\n", docsDir, err)
+		return
+	}
+
+	literal := strconv.Quote(string(spec))
+	if !strings.Contains(string(spec), "`") {
+		literal = "`" + string(spec) + "`"
+	}
+
+	data := map[string]interface{}{
+		"openapiSpec": literal,
+	}
+	conf.WriteTemplate("openapi", OpenAPITmpl, data, filepath.Join(docsDir, "openapi.go"), true)
 	return nil
 }
 
@@ -555,11 +1390,31 @@ func generateProtobufDefinitionFile(conf *dbmeta.Config, data map[string]interfa
 		return err
 	}
 
-	protofile := fmt.Sprintf("%s.proto", *sqlDatabase)
-	conf.WriteTemplate("protobuf", ProtobufTmpl, data, filepath.Join(*outDir, protofile), false)
+	protofile := filepath.Join(*outDir, fmt.Sprintf("%s.proto", *sqlDatabase))
+	if !*overwrite && !conf.Check && dbmeta.Exists(protofile) {
+		conf.OutputFiles = append(conf.OutputFiles, protofile)
+		fmt.Printf("not overwriting %s\n", protofile)
+		return nil
+	}
+
+	data["protobufImports"] = dbmeta.ProtobufImports(tableInfos)
+	conf.WriteTemplate("protobuf", ProtobufTmpl, data, protofile, false)
+	if conf.Check {
+		return nil
+	}
+
+	// the lock keeps field numbers stable across regenerations, it is saved with the proto file it numbered
+	if err = conf.ProtobufLock.Save(protobufLockFileName()); err != nil {
+		fmt.Printf("error writing %s - error: %v\n", protobufLockFileName(), err)
+		return err
+	}
 	return nil
 }
 
+func protobufLockFileName() string {
+	return filepath.Join(*outDir, fmt.Sprintf("%s.proto.lock", *sqlDatabase))
+}
+
 func generateProjectFiles(conf *dbmeta.Config, data map[string]interface{}) (err error) {
 
 	var GitIgnoreTmpl string
@@ -636,6 +1491,36 @@ func regenCmdLine() string {
 	buf.WriteString(fmt.Sprintf(" --model=%s", *modelPackageName))
 	buf.WriteString(fmt.Sprintf(" --dao=%s", *daoPackageName))
 	buf.WriteString(fmt.Sprintf(" --api=%s", *apiPackageName))
+	if *grpcGenerate {
+		buf.WriteString(fmt.Sprintf(" --grpc"))
+		buf.WriteString(fmt.Sprintf(" --grpc-pkg=%s", *grpcPackageName))
+		buf.WriteString(fmt.Sprintf(" --grpc-port=%d", *grpcPort))
+	}
+	if *testsGenerate {
+		buf.WriteString(fmt.Sprintf(" --generate-tests"))
+	}
+	if *tsGenerate {
+		buf.WriteString(fmt.Sprintf(" --typescript"))
+		buf.WriteString(fmt.Sprintf(" --typescript-dir=%s", *typescriptDir))
+	}
+	if *docsGenerate {
+		buf.WriteString(fmt.Sprintf(" --docs"))
+		buf.WriteString(fmt.Sprintf(" --docs-dir=%s", *docsDir))
+	}
+	if *clientGenerate {
+		buf.WriteString(fmt.Sprintf(" --client"))
+		buf.WriteString(fmt.Sprintf(" --client-pkg=%s", *clientPkgName))
+	}
+	if *graphqlGenerate {
+		buf.WriteString(fmt.Sprintf(" --graphql"))
+		buf.WriteString(fmt.Sprintf(" --graphql-pkg=%s", *graphqlPkgName))
+	}
+	if *importExport {
+		buf.WriteString(fmt.Sprintf(" --import-export"))
+	}
+	if *cacheGenerate {
+		buf.WriteString(fmt.Sprintf(" --cache"))
+	}
 	buf.WriteString(fmt.Sprintf(" --out=%s", "./"))
 	buf.WriteString(fmt.Sprintf(" --module=%s", *module))
 	if *AddJSONAnnotation {
@@ -652,9 +1537,19 @@ func regenCmdLine() string {
 	if *AddDBAnnotation {
 		buf.WriteString(fmt.Sprintf(" --db"))
 	}
+	if *AddValidateAnnotation {
+		buf.WriteString(fmt.Sprintf(" --validate"))
+	}
 	if *UseGureguTypes {
 		buf.WriteString(fmt.Sprintf(" --guregu"))
 	}
+	buf.WriteString(fmt.Sprintf(" --version-columns=%s", *versionColumnNames))
+	buf.WriteString(fmt.Sprintf(" --soft-delete-columns=%s", *softDeleteColumnNames))
+	buf.WriteString(fmt.Sprintf(" --created-at-columns=%s", *createdAtColumnNames))
+	buf.WriteString(fmt.Sprintf(" --updated-at-columns=%s", *updatedAtColumnNames))
+	if *restAPIGenerate {
+		buf.WriteString(fmt.Sprintf(" --router=%s", *routerName))
+	}
 	if *modGenerate {
 		buf.WriteString(fmt.Sprintf(" --mod"))
 	}
@@ -770,6 +1665,11 @@ func CreateGoSrcFileName(tableName string) string {
 	return name + ".go"
 }
 
+// CreateGoTestFileName name of the test file of the table, see CreateGoSrcFileName
+func CreateGoTestFileName(tableName string) string {
+	return strings.TrimSuffix(CreateGoSrcFileName(tableName), ".go") + "_test.go"
+}
+
 func LoadTemplate(filename string) (content string, err error) {
 	if *templateDir != "" {
 		fpath := filepath.Join(*templateDir, filename)